@@ -8,6 +8,8 @@ require github.com/aws/aws-lambda-go v1.49.0
 
 require github.com/aws/aws-sdk-go-v2/service/dynamodbstreams v1.25.6 // indirect
 
+require golang.org/x/image v0.18.0
+
 require (
 	github.com/aws/aws-sdk-go-v2 v1.36.5
 	github.com/aws/aws-sdk-go-v2/config v1.29.17
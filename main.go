@@ -1,10 +1,46 @@
 package main
 
 import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
 	"github.com/aws/aws-lambda-go/lambda"
 	"github.com/ndns-dev/ndns-tesseract/src/handlers"
+	"github.com/ndns-dev/ndns-tesseract/src/services"
 )
 
 func main() {
-	lambda.Start(handlers.HandleRequest)
+	// 실행 모드: lambda (기본), http (로컬 서버), bootstrap (DynamoDB 테이블 생성)
+	mode := flag.String("mode", getEnv("RUN_MODE", "lambda"), "run mode: lambda, http or bootstrap")
+	addr := flag.String("addr", getEnv("HTTP_ADDR", ":8080"), "listen address for http mode")
+	flag.Parse()
+
+	switch *mode {
+	case "lambda":
+		lambda.Start(handlers.HandleRequest)
+	case "http":
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		if err := handlers.StartHTTPServer(ctx, *addr); err != nil {
+			log.Fatalf("HTTP server failed: %v", err)
+		}
+	case "bootstrap":
+		if err := services.EnsureTables(context.Background()); err != nil {
+			log.Fatalf("DynamoDB bootstrap failed: %v", err)
+		}
+		log.Printf("DynamoDB tables are ready")
+	default:
+		log.Fatalf("unknown run mode: %s", *mode)
+	}
+}
+
+func getEnv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
 }
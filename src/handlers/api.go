@@ -2,12 +2,11 @@ package handlers
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
 	"log"
+	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
-	"time"
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/ndns-dev/ndns-tesseract/src/services"
@@ -15,51 +14,222 @@ import (
 	"github.com/ndns-dev/ndns-tesseract/src/utils"
 )
 
-// HandleAPIGatewayEvent는 API Gateway로부터의 요청을 처리합니다.
+// HandleAPIGatewayEvent는 API Gateway REST API(v1)로부터의 요청을 처리합니다.
 func HandleAPIGatewayEvent(ctx context.Context, e events.APIGatewayProxyRequest) (interface{}, error) {
-	var queueState customTypes.OcrQueueState
-	contentType := strings.ToLower(e.Headers["Content-Type"])
-
-	log.Printf("Received API Gateway event - Content-Type: %s", contentType)
-
-	if strings.Contains(contentType, "application/x-www-form-urlencoded") {
-		formData := utils.ParseFormURLEncoded(e.Body)
-		log.Printf("Parsed form data: %+v", formData)
-
-		// queueState에 값 할당
-		queueState.ReqId = formData["reqId"]
-		queueState.JobId = formData["jobId"]
-		queueState.CurrentPosition = customTypes.OcrPosition(formData["currentPosition"])
-		queueState.Is2025OrLater, _ = strconv.ParseBool(formData["is2025OrLater"])
-		queueState.RequestedAt, _ = time.Parse(time.RFC3339, formData["requestedAt"])
-		queueState.CrawlResult = &customTypes.CrawlResult{
-			Url:              formData["crawlResult.url"],
-			FirstParagraph:   formData["crawlResult.firstParagraph"],
-			LastParagraph:    formData["crawlResult.lastParagraph"],
-			Content:          formData["crawlResult.content"],
-			FirstImageUrl:    formData["crawlResult.firstImageUrl"],
-			LastImageUrl:     formData["crawlResult.lastImageUrl"],
-			FirstStickerUrl:  formData["crawlResult.firstStickerUrl"],
-			SecondStickerUrl: formData["crawlResult.secondStickerUrl"],
-			LastStickerUrl:   formData["crawlResult.lastStickerUrl"],
+	ctx = utils.WithRequestInfo(ctx, utils.RequestInfo{
+		RequestId: e.RequestContext.RequestID,
+		Format:    utils.ResponseFormatAPIGatewayV1,
+	})
+	log.Printf("Received API Gateway event - Content-Type: %s", headerValue(e.Headers, "Content-Type"))
+	body, err := decodeBody(e.Body, e.IsBase64Encoded)
+	if err != nil {
+		return utils.Response(ctx, nil, err)
+	}
+	return routeAPIRequest(ctx, apiRequest{
+		Method:      e.HTTPMethod,
+		Path:        e.Path,
+		JobId:       e.PathParameters["jobId"],
+		Query:       e.QueryStringParameters,
+		ContentType: headerValue(e.Headers, "Content-Type"),
+		Body:        body,
+	})
+}
+
+// HandleAPIGatewayV2Event는 API Gateway HTTP API(v2)로부터의 요청을 처리합니다.
+func HandleAPIGatewayV2Event(ctx context.Context, e events.APIGatewayV2HTTPRequest) (interface{}, error) {
+	ctx = utils.WithRequestInfo(ctx, utils.RequestInfo{
+		RequestId: e.RequestContext.RequestID,
+		Format:    utils.ResponseFormatAPIGatewayV2,
+	})
+	log.Printf("Received API Gateway v2 event - Content-Type: %s", headerValue(e.Headers, "Content-Type"))
+	body, err := decodeBody(e.Body, e.IsBase64Encoded)
+	if err != nil {
+		return utils.Response(ctx, nil, err)
+	}
+	return routeAPIRequest(ctx, apiRequest{
+		Method:      e.RequestContext.HTTP.Method,
+		Path:        e.RawPath,
+		JobId:       e.PathParameters["jobId"],
+		Query:       e.QueryStringParameters,
+		ContentType: headerValue(e.Headers, "Content-Type"),
+		Body:        body,
+	})
+}
+
+// HandleFunctionURLEvent는 Lambda Function URL로부터의 요청을 처리합니다.
+func HandleFunctionURLEvent(ctx context.Context, e events.LambdaFunctionURLRequest) (interface{}, error) {
+	ctx = utils.WithRequestInfo(ctx, utils.RequestInfo{
+		RequestId: e.RequestContext.RequestID,
+		Format:    utils.ResponseFormatFunctionURL,
+	})
+	log.Printf("Received Function URL event - Content-Type: %s", headerValue(e.Headers, "Content-Type"))
+	body, err := decodeBody(e.Body, e.IsBase64Encoded)
+	if err != nil {
+		return utils.Response(ctx, nil, err)
+	}
+	return routeAPIRequest(ctx, apiRequest{
+		Method:      e.RequestContext.HTTP.Method,
+		Path:        e.RawPath,
+		Query:       e.QueryStringParameters,
+		ContentType: headerValue(e.Headers, "Content-Type"),
+		Body:        body,
+	})
+}
+
+// apiRequest는 HTTP 이벤트 형식(API Gateway v1/v2, Function URL)에서 라우팅에 필요한 값만 모은 것입니다.
+type apiRequest struct {
+	Method      string
+	Path        string
+	JobId       string // API Gateway 경로 매개변수 {jobId} (없으면 경로에서 찾음)
+	Query       map[string]string
+	ContentType string
+	Body        string
+}
+
+// 작업 조회 경로 구간입니다 (GET /jobs/{jobId}, GET /jobs/{jobId}/results). 스테이지 접두사가 붙어도 찾습니다.
+const (
+	jobsPathSegment    = "/jobs/"
+	resultsPathSegment = "/results"
+)
+
+// jobsRoute는 작업 조회 경로를 해석한 결과입니다.
+type jobsRoute struct {
+	JobId   string
+	Results bool // .../jobs/{jobId}/results
+}
+
+// routeAPIRequest는 GET .../jobs/{jobId} 요청을 작업 상태 조회로, GET .../jobs/{jobId}/results 요청을 결과 목록 조회로,
+// GET 외의 요청을 OCR 작업 제출로 보냅니다. 작업 조회 경로가 아닌 GET 요청은 NOT_FOUND로 응답합니다.
+func routeAPIRequest(ctx context.Context, req apiRequest) (interface{}, error) {
+	if strings.EqualFold(req.Method, http.MethodGet) {
+		route, ok := parseJobsPath(req.Path)
+		if req.JobId != "" {
+			route.JobId, ok = req.JobId, true
+		}
+		if !ok {
+			return utils.Response(ctx, nil, utils.Errorf(customTypes.ErrorCodeNotFound, "no route for GET %s", req.Path))
+		}
+		if route.JobId == "" {
+			route.JobId = req.Query["jobId"]
+		}
+		if route.Results {
+			return handleJobResults(ctx, route.JobId, req.Query)
+		}
+		return handleJobStatus(ctx, route.JobId, req.Query["position"])
+	}
+	return handleOcrSubmission(ctx, req.ContentType, req.Body)
+}
+
+// parseJobsPath는 .../jobs, .../jobs/{jobId}, .../jobs/{jobId}/results 형태의 경로를 해석합니다.
+// 작업 조회 경로가 아니면 false를 반환합니다.
+func parseJobsPath(path string) (jobsRoute, bool) {
+	path = strings.TrimRight(path, "/")
+	if strings.HasSuffix(path, strings.TrimSuffix(jobsPathSegment, "/")) {
+		return jobsRoute{}, true
+	}
+	idx := strings.LastIndex(path, jobsPathSegment)
+	if idx < 0 {
+		return jobsRoute{}, false
+	}
+	var route jobsRoute
+	rest := path[idx+len(jobsPathSegment):]
+	if trimmed, ok := strings.CutSuffix(rest, resultsPathSegment); ok && trimmed != "" {
+		rest, route.Results = trimmed, true
+	}
+	if strings.Contains(rest, "/") {
+		return jobsRoute{}, false
+	}
+	jobId, err := url.PathUnescape(rest)
+	if err != nil {
+		return route, true
+	}
+	route.JobId = jobId
+	return route, true
+}
+
+// handleJobStatus는 작업 상태를 조회합니다. position이 있으면 해당 위치만 반환합니다.
+func handleJobStatus(ctx context.Context, jobId, position string) (interface{}, error) {
+	var fieldErrs customTypes.FieldErrors
+	if jobId == "" {
+		fieldErrs = append(fieldErrs, customTypes.FieldError{Field: "jobId", Message: "is required"})
+	}
+	if position != "" && position != customTypes.JobPositionAll && !customTypes.OcrPosition(position).IsValid() {
+		fieldErrs = append(fieldErrs, customTypes.FieldError{Field: "position", Message: "unknown position " + strconv.Quote(position)})
+	}
+	if len(fieldErrs) > 0 {
+		return utils.Response(ctx, nil, utils.NewError(customTypes.ErrorCodeValidation, fieldErrs))
+	}
+
+	log.Printf("Received job status request - JobId: %s, Position: %s", jobId, position)
+	status, err := ocrService.GetJobStatus(ctx, jobId, position)
+	return utils.Response(ctx, status, err)
+}
+
+// handleJobResults는 작업에서 저장한 위치별 OCR 결과를 한 페이지씩 조회합니다.
+// position, limit, cursor 쿼리 매개변수를 지원합니다.
+func handleJobResults(ctx context.Context, jobId string, query map[string]string) (interface{}, error) {
+	var fieldErrs customTypes.FieldErrors
+	if jobId == "" {
+		fieldErrs = append(fieldErrs, customTypes.FieldError{Field: "jobId", Message: "is required"})
+	}
+	resultQuery := services.ResultQuery{Position: customTypes.OcrPosition(query["position"]), Cursor: query["cursor"]}
+	if resultQuery.Position != "" && !resultQuery.Position.IsValid() {
+		fieldErrs = append(fieldErrs, customTypes.FieldError{Field: "position", Message: "unknown position " + strconv.Quote(query["position"])})
+	}
+	if raw := query["limit"]; raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit <= 0 {
+			fieldErrs = append(fieldErrs, customTypes.FieldError{Field: "limit", Message: "must be a positive integer"})
+		}
+		resultQuery.Limit = limit
+	}
+	if len(fieldErrs) > 0 {
+		return utils.Response(ctx, nil, utils.NewError(customTypes.ErrorCodeValidation, fieldErrs))
+	}
+
+	log.Printf("Received job results request - JobId: %s, Position: %s", jobId, resultQuery.Position)
+	page, err := ocrService.ListJobResults(ctx, jobId, resultQuery)
+	return utils.Response(ctx, page, err)
+}
+
+// headerValue는 대소문자를 구분하지 않고 헤더 값을 찾습니다. HTTP API와 Function URL은 헤더 이름을 소문자로 전달합니다.
+func headerValue(headers map[string]string, name string) string {
+	if value, ok := headers[name]; ok {
+		return value
+	}
+	for key, value := range headers {
+		if strings.EqualFold(key, name) {
+			return value
 		}
+	}
+	return ""
+}
 
-		// 디버그 로깅
-		log.Printf("Constructed queueState from form data - ReqId: %s, JobId: %s, Position: %s, URL: %s",
-			queueState.ReqId,
-			queueState.JobId,
-			queueState.CurrentPosition,
-			queueState.CrawlResult.Url)
+// handleOcrSubmission은 폼 또는 JSON 본문을 OcrQueueState로 해석해 OCR 워크플로우를 실행합니다.
+func handleOcrSubmission(ctx context.Context, contentType, body string) (interface{}, error) {
+	var queueState customTypes.OcrQueueState
+	var err error
 
-	} else {
-		log.Printf("Attempting to parse request body as JSON")
-		err := json.Unmarshal([]byte(e.Body), &queueState)
-		if err != nil {
-			return utils.Response(utils.ErrorHandler(ctx, fmt.Errorf("could not unmarshal HTTP request body: %w", err), queueState.JobId, queueState.CrawlResult.Url, "HTTPRequestUnmarshal"))
+	if strings.Contains(strings.ToLower(contentType), "application/x-www-form-urlencoded") {
+		var values url.Values
+		values, err = url.ParseQuery(body)
+		if err == nil {
+			queueState, err = customTypes.DecodeOcrQueueStateForm(values)
 		}
-		log.Printf("Successfully parsed JSON request body")
+	} else {
+		queueState, err = customTypes.DecodeOcrQueueStateJSON([]byte(body))
+	}
+	if err != nil {
+		errResp, handlerErr := utils.ErrorHandler(ctx, queueStateError(err, "HTTP request body"), queueState.JobId, queueState.CrawlUrl(), "HTTPRequestDecode")
+		return utils.Response(ctx, errResp, handlerErr)
 	}
 
-	errResp, err := services.HandleOcrWorkflow(ctx, queueState)
-	return utils.Response(errResp, err)
+	log.Printf("Decoded HTTP request - ReqId: %s, JobId: %s, Position: %s, URL: %s",
+		queueState.ReqId,
+		queueState.JobId,
+		queueState.CurrentPosition,
+		queueState.CrawlUrl())
+
+	result, err := ocrService.HandleOcrWorkflow(ctx, queueState)
+	return utils.Response(ctx, result, err)
 }
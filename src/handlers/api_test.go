@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/ndns-dev/ndns-tesseract/src/services"
+	customTypes "github.com/ndns-dev/ndns-tesseract/src/types"
+	"github.com/ndns-dev/ndns-tesseract/src/utils"
+)
+
+// apiGet은 API Gateway v1 형식으로 GET 요청을 라우팅하고 상태 코드와 본문을 반환합니다.
+func apiGet(t *testing.T, req apiRequest) (int, customTypes.ApiResponse) {
+	t.Helper()
+	ctx := utils.WithRequestInfo(context.Background(), utils.RequestInfo{RequestId: "req-1", Format: utils.ResponseFormatAPIGatewayV1})
+	req.Method = http.MethodGet
+	raw, err := routeAPIRequest(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp := raw.(*events.APIGatewayProxyResponse)
+	var body customTypes.ApiResponse
+	if err := json.Unmarshal([]byte(resp.Body), &body); err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode, body
+}
+
+func TestRouteAPIRequestGet(t *testing.T) {
+	SetOcrService(services.NewOcrService(utils.NewFakeOcrEngine(""),
+		services.WithResultStore(services.NewMemoryResultStore()),
+		services.WithResultCache(nil),
+		services.WithJobStateStore(services.NewMemoryJobStateStore(services.JobStateConfig{LeaseDuration: time.Minute, MaxAttempts: 3})),
+	))
+
+	tests := []struct {
+		name       string
+		req        apiRequest
+		wantStatus int
+		wantCode   customTypes.ErrorCode
+	}{
+		{name: "jobs path", req: apiRequest{Path: "/jobs/job-1"}, wantStatus: http.StatusNotFound, wantCode: customTypes.ErrorCodeJobNotFound},
+		{name: "stage prefix", req: apiRequest{Path: "/prod/jobs/job-1"}, wantStatus: http.StatusNotFound, wantCode: customTypes.ErrorCodeJobNotFound},
+		{name: "path parameter", req: apiRequest{Path: "/v1/status/job-1", JobId: "job-1"}, wantStatus: http.StatusNotFound, wantCode: customTypes.ErrorCodeJobNotFound},
+		{name: "jobs path with query", req: apiRequest{Path: "/jobs", Query: map[string]string{"jobId": "job-1"}}, wantStatus: http.StatusNotFound, wantCode: customTypes.ErrorCodeJobNotFound},
+		{name: "jobs path without jobId", req: apiRequest{Path: "/jobs/"}, wantStatus: http.StatusBadRequest, wantCode: customTypes.ErrorCodeValidation},
+		{name: "unknown position", req: apiRequest{Path: "/jobs/job-1", Query: map[string]string{"position": "Middle"}}, wantStatus: http.StatusBadRequest, wantCode: customTypes.ErrorCodeValidation},
+		{name: "other path", req: apiRequest{Path: "/health"}, wantStatus: http.StatusNotFound, wantCode: customTypes.ErrorCodeNotFound},
+		{name: "other path with jobId query", req: apiRequest{Path: "/ocr", Query: map[string]string{"jobId": "job-1"}}, wantStatus: http.StatusNotFound, wantCode: customTypes.ErrorCodeNotFound},
+		{name: "similar path", req: apiRequest{Path: "/myjobs"}, wantStatus: http.StatusNotFound, wantCode: customTypes.ErrorCodeNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := apiGet(t, tt.req)
+			if status != tt.wantStatus || body.Error == nil || body.Error.ErrorCode != tt.wantCode {
+				t.Errorf("got %d %+v, want %d %s", status, body.Error, tt.wantStatus, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestRouteAPIRequestJobResults(t *testing.T) {
+	store := services.NewMemoryResultStore()
+	for _, result := range []customTypes.OcrResult{
+		{JobId: "job-1", Position: customTypes.OcrPositionFirstImage, ImageUrl: "https://img/1", OcrText: "first"},
+		{JobId: "job-1", Position: customTypes.OcrPositionLastImage, ImageUrl: "https://img/2", OcrText: "last"},
+		{JobId: "job-2", Position: customTypes.OcrPositionFirstImage, ImageUrl: "https://img/3", OcrText: "other"},
+	} {
+		if err := store.Save(context.Background(), result); err != nil {
+			t.Fatal(err)
+		}
+	}
+	SetOcrService(services.NewOcrService(utils.NewFakeOcrEngine(""),
+		services.WithResultStore(store),
+		services.WithResultCache(nil),
+		services.WithJobStateStore(services.NewMemoryJobStateStore(services.JobStateConfig{LeaseDuration: time.Minute, MaxAttempts: 3})),
+	))
+
+	tests := []struct {
+		name       string
+		req        apiRequest
+		wantStatus int
+		wantCode   customTypes.ErrorCode
+		wantTexts  []string
+	}{
+		{name: "all positions", req: apiRequest{Path: "/jobs/job-1/results"}, wantStatus: http.StatusOK, wantTexts: []string{"first", "last"}},
+		{name: "path parameter", req: apiRequest{Path: "/prod/jobs/job-1/results/", JobId: "job-1"}, wantStatus: http.StatusOK, wantTexts: []string{"first", "last"}},
+		{name: "one position", req: apiRequest{Path: "/jobs/job-1/results", Query: map[string]string{"position": "LastImageUrl"}}, wantStatus: http.StatusOK, wantTexts: []string{"last"}},
+		{name: "unknown job", req: apiRequest{Path: "/jobs/job-9/results"}, wantStatus: http.StatusOK, wantTexts: []string{}},
+		{name: "invalid limit", req: apiRequest{Path: "/jobs/job-1/results", Query: map[string]string{"limit": "0"}}, wantStatus: http.StatusBadRequest, wantCode: customTypes.ErrorCodeValidation},
+		{name: "invalid cursor", req: apiRequest{Path: "/jobs/job-1/results", Query: map[string]string{"cursor": "nope"}}, wantStatus: http.StatusBadRequest, wantCode: customTypes.ErrorCodeValidation},
+		{name: "unknown sub path", req: apiRequest{Path: "/jobs/job-1/other"}, wantStatus: http.StatusNotFound, wantCode: customTypes.ErrorCodeNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := apiGet(t, tt.req)
+			if status != tt.wantStatus {
+				t.Fatalf("status = %d (%+v), want %d", status, body.Error, tt.wantStatus)
+			}
+			if tt.wantCode != "" {
+				if body.Error == nil || body.Error.ErrorCode != tt.wantCode {
+					t.Errorf("error = %+v, want %s", body.Error, tt.wantCode)
+				}
+				return
+			}
+			data, _ := json.Marshal(body.Data)
+			var page services.ResultPage
+			if err := json.Unmarshal(data, &page); err != nil {
+				t.Fatal(err)
+			}
+			texts := []string{}
+			for _, result := range page.Results {
+				texts = append(texts, result.OcrText)
+			}
+			if !reflect.DeepEqual(texts, tt.wantTexts) {
+				t.Errorf("texts = %v, want %v", texts, tt.wantTexts)
+			}
+		})
+	}
+}
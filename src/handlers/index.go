@@ -6,25 +6,68 @@ import (
 	"log"
 
 	"github.com/aws/aws-lambda-go/events"
+	"github.com/ndns-dev/ndns-tesseract/src/services"
+	customTypes "github.com/ndns-dev/ndns-tesseract/src/types"
+	"github.com/ndns-dev/ndns-tesseract/src/utils"
 )
 
+// ocrWorkflowService는 핸들러가 호출하는 OCR 서비스 동작입니다. 테스트에서는 가짜 서비스로 바꿉니다.
+type ocrWorkflowService interface {
+	HandleOcrWorkflow(ctx context.Context, queueState customTypes.OcrQueueState) (*customTypes.OcrResult, error)
+	GetJobStatus(ctx context.Context, jobId, position string) (*customTypes.JobStatusResponse, error)
+	ListJobResults(ctx context.Context, jobId string, query services.ResultQuery) (*services.ResultPage, error)
+}
+
+// ocrService는 핸들러들이 공유하는 OCR 서비스입니다.
+var ocrService ocrWorkflowService = services.NewOcrService(utils.NewTesseractEngineFromEnv())
+
+// SetOcrService는 핸들러가 사용할 OCR 서비스를 교체합니다.
+func SetOcrService(service *services.OcrService) {
+	ocrService = service
+}
+
+// HandleRequest는 Lambda 이벤트의 형식을 판별해 알맞은 핸들러로 전달합니다.
+// 알 수 없는 형식의 이벤트는 오류를 반환합니다.
 func HandleRequest(ctx context.Context, event json.RawMessage) (interface{}, error) {
-	// SQS 이벤트 체크
-	var sqsEvent events.SQSEvent
-	if err := json.Unmarshal(event, &sqsEvent); err == nil {
-		if len(sqsEvent.Records) > 0 && sqsEvent.Records[0].EventSource == "aws:sqs" {
-			return HandleSQSEvent(ctx, sqsEvent)
-		}
+	kind, err := detectEventKind(event)
+	if err != nil {
+		log.Printf("Unsupported event type: %v (%s)", err, truncateForLog(string(event), 500))
+		return nil, err
 	}
+	log.Printf("Routing %s event", kind)
 
-	// API Gateway 이벤트 체크
-	var apiEvent events.APIGatewayProxyRequest
-	if err := json.Unmarshal(event, &apiEvent); err == nil {
-		if apiEvent.Body != "" {
-			return HandleAPIGatewayEvent(ctx, apiEvent)
+	switch kind {
+	case eventKindSQS:
+		var e events.SQSEvent
+		if err := json.Unmarshal(event, &e); err != nil {
+			return nil, invalidEventError(kind, err)
 		}
+		return HandleSQSEvent(ctx, e)
+	case eventKindAPIGatewayV1:
+		var e events.APIGatewayProxyRequest
+		if err := json.Unmarshal(event, &e); err != nil {
+			return nil, invalidEventError(kind, err)
+		}
+		return HandleAPIGatewayEvent(ctx, e)
+	case eventKindAPIGatewayV2:
+		var e events.APIGatewayV2HTTPRequest
+		if err := json.Unmarshal(event, &e); err != nil {
+			return nil, invalidEventError(kind, err)
+		}
+		return HandleAPIGatewayV2Event(ctx, e)
+	case eventKindFunctionURL:
+		var e events.LambdaFunctionURLRequest
+		if err := json.Unmarshal(event, &e); err != nil {
+			return nil, invalidEventError(kind, err)
+		}
+		return HandleFunctionURLEvent(ctx, e)
+	case eventKindEventBridge:
+		var e events.EventBridgeEvent
+		if err := json.Unmarshal(event, &e); err != nil {
+			return nil, invalidEventError(kind, err)
+		}
+		return HandleEventBridgeEvent(ctx, e)
+	default:
+		return HandleDirectInvoke(ctx, event)
 	}
-
-	log.Printf("Unsupported event type: %s", string(event))
-	return nil, nil
 }
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	"github.com/aws/aws-lambda-go/events"
+	customTypes "github.com/ndns-dev/ndns-tesseract/src/types"
+	"github.com/ndns-dev/ndns-tesseract/src/utils"
+)
+
+// HandleDirectInvoke는 OcrQueueState JSON을 그대로 전달한 직접 호출(lambda invoke)을 처리합니다.
+// 호출자가 결과를 기다리므로 OcrResult를 반환하고, 실패하면 오류를 그대로 반환합니다.
+func HandleDirectInvoke(ctx context.Context, payload json.RawMessage) (interface{}, error) {
+	queueState, err := customTypes.DecodeOcrQueueStateJSON(payload)
+	if err != nil {
+		return nil, queueStateError(err, "direct invoke payload")
+	}
+
+	log.Printf("Received direct invoke - JobId: %s, Position: %s", queueState.JobId, queueState.CurrentPosition)
+	return ocrService.HandleOcrWorkflow(ctx, queueState)
+}
+
+// HandleEventBridgeEvent는 detail에 OcrQueueState가 담긴 EventBridge 이벤트를 처리합니다.
+// 비동기 호출은 오류를 반환하면 Lambda가 재시도하므로, 재시도할 수 없는 오류는 로그만 남기고 성공으로 처리합니다.
+func HandleEventBridgeEvent(ctx context.Context, e events.EventBridgeEvent) (interface{}, error) {
+	log.Printf("Received EventBridge event - Id: %s, Source: %s, DetailType: %s", e.ID, e.Source, e.DetailType)
+
+	queueState, err := customTypes.DecodeOcrQueueStateJSON(e.Detail)
+	if err != nil {
+		err = queueStateError(err, "EventBridge detail")
+	} else {
+		var result *customTypes.OcrResult
+		result, err = ocrService.HandleOcrWorkflow(ctx, queueState)
+		if err == nil {
+			return result, nil
+		}
+	}
+
+	if utils.ErrorCodeOf(err) == customTypes.ErrorCodeJobDuplicate {
+		log.Printf("Skipping duplicate EventBridge event %s: %v", e.ID, err)
+		return nil, nil
+	}
+	if !utils.IsRetryable(err) {
+		log.Printf("Dropping EventBridge event %s due to non-retryable error [%s]: %v", e.ID, utils.ErrorCodeOf(err), err)
+		utils.WebhookLog("ndns-tesseract: EVENTBRIDGE PERMANENT FAILURE: %s", map[string]interface{}{
+			"eventId":   e.ID,
+			"jobId":     queueState.JobId,
+			"errorCode": utils.ErrorCodeOf(err),
+			"error":     err.Error(),
+		})
+		return nil, nil
+	}
+	return nil, err
+}
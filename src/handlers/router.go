@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	customTypes "github.com/ndns-dev/ndns-tesseract/src/types"
+	"github.com/ndns-dev/ndns-tesseract/src/utils"
+)
+
+// eventKind는 Lambda 이벤트의 형식입니다.
+type eventKind string
+
+const (
+	eventKindSQS          eventKind = "sqs"
+	eventKindAPIGatewayV1 eventKind = "apigateway-v1"
+	eventKindAPIGatewayV2 eventKind = "apigateway-v2"
+	eventKindFunctionURL  eventKind = "function-url"
+	eventKindEventBridge  eventKind = "eventbridge"
+	eventKindDirect       eventKind = "direct"
+)
+
+// eventProbe는 이벤트 형식 판별에 필요한 필드만 읽기 위한 구조체입니다.
+type eventProbe struct {
+	Records []struct {
+		EventSource string `json:"eventSource"`
+	} `json:"Records"`
+	Version        string `json:"version"`
+	HTTPMethod     string `json:"httpMethod"`
+	RequestContext struct {
+		DomainName string `json:"domainName"`
+		HTTP       struct {
+			Method string `json:"method"`
+		} `json:"http"`
+	} `json:"requestContext"`
+	Source      string          `json:"source"`
+	DetailType  string          `json:"detail-type"`
+	Detail      json.RawMessage `json:"detail"`
+	JobId       string          `json:"jobId"`
+	CrawlResult json.RawMessage `json:"crawlResult"`
+}
+
+// detectEventKind는 이벤트 JSON의 모양으로 형식을 판별합니다.
+// 직접 호출(lambda invoke)은 jobId 또는 crawlResult가 있는 OcrQueueState 객체로 판별합니다.
+func detectEventKind(event json.RawMessage) (eventKind, error) {
+	var probe eventProbe
+	if err := json.Unmarshal(event, &probe); err != nil {
+		return "", utils.Errorf(customTypes.ErrorCodeInvalidRequest, "event is not a JSON object: %w", err)
+	}
+
+	switch {
+	case len(probe.Records) > 0 && probe.Records[0].EventSource == "aws:sqs":
+		return eventKindSQS, nil
+	case probe.Version == "2.0" && probe.RequestContext.HTTP.Method != "":
+		// Function URL은 HTTP API 2.0 페이로드와 같은 모양이며 도메인으로 구분합니다.
+		if strings.Contains(probe.RequestContext.DomainName, ".lambda-url.") {
+			return eventKindFunctionURL, nil
+		}
+		return eventKindAPIGatewayV2, nil
+	case probe.HTTPMethod != "":
+		return eventKindAPIGatewayV1, nil
+	case probe.Source != "" && probe.DetailType != "" && len(probe.Detail) > 0:
+		return eventKindEventBridge, nil
+	case probe.JobId != "" || len(probe.CrawlResult) > 0:
+		return eventKindDirect, nil
+	}
+	return "", utils.Errorf(customTypes.ErrorCodeInvalidRequest, "unsupported event type")
+}
+
+// invalidEventError는 판별한 형식으로 이벤트를 디코딩하지 못했을 때의 오류를 만듭니다.
+func invalidEventError(kind eventKind, err error) error {
+	return utils.Errorf(customTypes.ErrorCodeInvalidRequest, "could not decode %s event: %w", kind, err)
+}
+
+// decodeBody는 HTTP 이벤트 본문을 반환합니다. isBase64Encoded가 설정된 본문은 디코딩합니다.
+func decodeBody(body string, isBase64Encoded bool) (string, error) {
+	if !isBase64Encoded {
+		return body, nil
+	}
+	decoded, err := base64.StdEncoding.DecodeString(body)
+	if err != nil {
+		return "", utils.Errorf(customTypes.ErrorCodeInvalidRequest, "could not decode base64 request body: %w", err)
+	}
+	return string(decoded), nil
+}
+
+// truncateForLog는 로그에 남길 문자열을 max 바이트로 자릅니다.
+func truncateForLog(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	return s[:max] + "..."
+}
+
+// queueStateError는 OcrQueueState 해석 오류에 오류 코드를 붙입니다.
+// 필드 검증 오류는 VALIDATION_FAILED, 본문 자체를 해석할 수 없으면 INVALID_REQUEST입니다.
+func queueStateError(err error, source string) error {
+	var fieldErrs customTypes.FieldErrors
+	if errors.As(err, &fieldErrs) {
+		return utils.NewError(customTypes.ErrorCodeValidation, err)
+	}
+	return utils.Errorf(customTypes.ErrorCodeInvalidRequest, "could not decode %s: %w", source, err)
+}
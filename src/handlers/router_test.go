@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"encoding/json"
+	"testing"
+
+	customTypes "github.com/ndns-dev/ndns-tesseract/src/types"
+	"github.com/ndns-dev/ndns-tesseract/src/utils"
+)
+
+func TestDetectEventKind(t *testing.T) {
+	tests := []struct {
+		name  string
+		event string
+		want  eventKind
+	}{
+		{
+			name:  "sqs",
+			event: `{"Records":[{"messageId":"m-1","eventSource":"aws:sqs","body":"{}"}]}`,
+			want:  eventKindSQS,
+		},
+		{
+			name:  "api gateway v1",
+			event: `{"resource":"/ocr","path":"/ocr","httpMethod":"POST","headers":{"Content-Type":"application/json"},"requestContext":{"requestId":"r-1","stage":"prod"},"body":"{}"}`,
+			want:  eventKindAPIGatewayV1,
+		},
+		{
+			name:  "api gateway v2",
+			event: `{"version":"2.0","routeKey":"POST /ocr","rawPath":"/ocr","requestContext":{"domainName":"abc123.execute-api.ap-northeast-2.amazonaws.com","http":{"method":"POST","path":"/ocr"},"requestId":"r-1"},"body":"{}"}`,
+			want:  eventKindAPIGatewayV2,
+		},
+		{
+			name:  "function url",
+			event: `{"version":"2.0","routeKey":"$default","rawPath":"/","requestContext":{"domainName":"abc123.lambda-url.ap-northeast-2.on.aws","http":{"method":"POST","path":"/"},"requestId":"r-1"},"body":"{}"}`,
+			want:  eventKindFunctionURL,
+		},
+		{
+			name:  "eventbridge",
+			event: `{"version":"0","id":"e-1","source":"ndns.crawler","detail-type":"OcrRequested","detail":{"jobId":"job-1"}}`,
+			want:  eventKindEventBridge,
+		},
+		{
+			name:  "direct invoke with jobId",
+			event: `{"jobId":"job-1","currentPosition":"FirstImage"}`,
+			want:  eventKindDirect,
+		},
+		{
+			name:  "direct invoke with crawlResult only",
+			event: `{"crawlResult":{"firstImageUrl":"https://example.com/a.png"}}`,
+			want:  eventKindDirect,
+		},
+		{
+			// v2 버전 필드만 있고 HTTP 메서드가 없으면 HTTP 이벤트로 보지 않습니다.
+			name:  "version without http method",
+			event: `{"version":"2.0","requestContext":{}}`,
+		},
+		{
+			name:  "non-sqs records",
+			event: `{"Records":[{"eventSource":"aws:s3"}]}`,
+		},
+		{
+			name:  "eventbridge without detail",
+			event: `{"source":"ndns.crawler","detail-type":"OcrRequested"}`,
+		},
+		{name: "empty object", event: `{}`},
+		{name: "array", event: `[]`},
+		{name: "not json", event: `not json`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := detectEventKind(json.RawMessage(tt.event))
+			if tt.want == "" {
+				if code := utils.ErrorCodeOf(err); err == nil || code != customTypes.ErrorCodeInvalidRequest {
+					t.Fatalf("detectEventKind() = %q, %v, want an %s error", got, err, customTypes.ErrorCodeInvalidRequest)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("detectEventKind() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("detectEventKind() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
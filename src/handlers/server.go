@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/ndns-dev/ndns-tesseract/src/utils"
+)
+
+// NewHTTPHandler는 로컬 실행용 HTTP 라우트를 구성합니다.
+// Lambda와 동일한 HandleAPIGatewayEvent 로직을 net/http 위에서 제공합니다.
+func NewHTTPHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /health", handleHealth)
+	mux.HandleFunc("POST /ocr", handleProxyRequest)
+	mux.HandleFunc("GET /jobs/{jobId}", handleProxyRequest)
+	mux.HandleFunc("GET /jobs/{jobId}/results", handleProxyRequest)
+	return mux
+}
+
+// StartHTTPServer는 ctx가 취소될 때까지 로컬 HTTP 서버를 실행합니다.
+func StartHTTPServer(ctx context.Context, addr string) error {
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           NewHTTPHandler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			log.Printf("HTTP server shutdown error: %v", err)
+		}
+	}()
+
+	log.Printf("HTTP server listening on %s", addr)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// handleHealth는 헬스 체크 요청에 응답합니다.
+func handleHealth(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
+}
+
+// handleProxyRequest는 HTTP 요청을 API Gateway 이벤트로 변환해 Lambda와 같은 경로로 처리합니다.
+func handleProxyRequest(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "failed to read request body", http.StatusBadRequest)
+		return
+	}
+
+	resp, err := HandleAPIGatewayEvent(r.Context(), toAPIGatewayRequest(r, body))
+	writeHandlerResponse(w, resp, err)
+}
+
+// toAPIGatewayRequest는 net/http 요청을 API Gateway 프록시 요청으로 변환합니다.
+func toAPIGatewayRequest(r *http.Request, body []byte) events.APIGatewayProxyRequest {
+	headers := make(map[string]string, len(r.Header))
+	for key, values := range r.Header {
+		if len(values) > 0 {
+			headers[key] = values[0]
+		}
+	}
+
+	query := make(map[string]string)
+	for key, values := range r.URL.Query() {
+		if len(values) > 0 {
+			query[key] = values[0]
+		}
+	}
+
+	requestId := r.Header.Get(utils.RequestIdHeader)
+	if requestId == "" {
+		requestId = utils.NewRequestId()
+	}
+
+	return events.APIGatewayProxyRequest{
+		RequestContext:        events.APIGatewayProxyRequestContext{RequestID: requestId},
+		HTTPMethod:            r.Method,
+		Path:                  r.URL.Path,
+		PathParameters:        map[string]string{"jobId": r.PathValue("jobId")},
+		Headers:               headers,
+		QueryStringParameters: query,
+		Body:                  string(body),
+	}
+}
+
+// writeHandlerResponse는 핸들러 결과를 HTTP 응답으로 기록합니다.
+func writeHandlerResponse(w http.ResponseWriter, resp interface{}, err error) {
+	if err != nil {
+		log.Printf("Handler error: %v", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	proxyResp, ok := resp.(*events.APIGatewayProxyResponse)
+	if !ok || proxyResp == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	for key, value := range proxyResp.Headers {
+		w.Header().Set(key, value)
+	}
+	w.WriteHeader(proxyResp.StatusCode)
+	io.WriteString(w, proxyResp.Body)
+}
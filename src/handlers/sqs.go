@@ -2,78 +2,175 @@ package handlers
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"log"
+	"os"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/aws/aws-lambda-go/events"
-	"github.com/ndns-dev/ndns-tesseract/src/services"
 	customTypes "github.com/ndns-dev/ndns-tesseract/src/types"
 	"github.com/ndns-dev/ndns-tesseract/src/utils"
 )
 
-// getString은 map에서 안전하게 문자열 값을 추출합니다.
-func getString(m map[string]interface{}, key string) string {
-	if val, ok := m[key]; ok {
-		if str, ok := val.(string); ok {
-			return str
+// messageAttributeStrings는 SQS 메시지 속성 가운데 문자열/숫자 값을 꺼냅니다.
+func messageAttributeStrings(attributes map[string]events.SQSMessageAttribute) map[string]string {
+	values := make(map[string]string, len(attributes))
+	for key, attr := range attributes {
+		if attr.StringValue != nil {
+			values[key] = *attr.StringValue
 		}
 	}
-	return ""
+	return values
+}
+
+// SQS 배치 동시 처리 기본 설정
+const (
+	defaultSQSConcurrency  = 4
+	defaultSQSMinRemaining = 5 * time.Second
+	sqsConcurrencyEnv      = "SQS_CONCURRENCY"
+	sqsMinRemainingMsEnv   = "SQS_MIN_REMAINING_MS"
+)
+
+// sqsRecordResult는 SQS 메시지 하나의 처리 결과입니다.
+type sqsRecordResult struct {
+	MessageId string
+	Err       error
+	Skipped   bool // 남은 실행 시간이 부족해 처리하지 않음
+	Duration  time.Duration
 }
 
 // HandleSQSEvent는 SQS로부터의 메시지를 처리합니다.
+// 메시지는 SQS_CONCURRENCY 개의 워커가 동시에 처리하며, Lambda의 남은 실행 시간이
+// SQS_MIN_REMAINING_MS 보다 짧아지면 새 메시지를 시작하지 않고 실패로 보고합니다.
+// 재시도가 필요한 메시지만 BatchItemFailures로 보고하며, 이벤트 소스 매핑에
+// ReportBatchItemFailures가 설정되어 있어야 성공한 메시지가 다시 전달되지 않습니다.
+// 재시도할 수 없는 코드의 오류가 발생한 메시지는 로그를 남기고 큐에서 제거되도록 성공으로 처리합니다.
 func HandleSQSEvent(ctx context.Context, e events.SQSEvent) (interface{}, error) {
-	for _, record := range e.Records {
-		var queueState customTypes.OcrQueueState
-		var bodyMap map[string]interface{}
+	results := processSQSRecords(ctx, e.Records, sqsConcurrency(), sqsMinRemaining())
 
-		err := json.Unmarshal([]byte(record.Body), &bodyMap)
-		if err != nil {
-			return utils.Response(utils.ErrorHandler(ctx, fmt.Errorf("could not unmarshal SQS message body: %w", err), "", "", "SQSMessageUnmarshal"))
+	response := events.SQSEventResponse{
+		BatchItemFailures: []events.SQSBatchItemFailure{},
+	}
+	for _, result := range results {
+		if result.Err == nil {
+			log.Printf("SQS message %s processed in %s", result.MessageId, result.Duration)
+			continue
+		}
+
+		if utils.ErrorCodeOf(result.Err) == customTypes.ErrorCodeJobDuplicate {
+			log.Printf("Skipping duplicate SQS message %s: %v", result.MessageId, result.Err)
+			continue
 		}
 
-		// queueState에 값 할당
-		queueState.ReqId = getString(bodyMap, "reqId")
-		queueState.JobId = getString(bodyMap, "jobId")
-		queueState.CurrentPosition = customTypes.OcrPosition(getString(bodyMap, "currentPosition"))
-		queueState.Is2025OrLater, _ = strconv.ParseBool(getString(bodyMap, "is2025OrLater"))
-		requestedAt := getString(bodyMap, "requestedAt")
-		if requestedAt != "" {
-			queueState.RequestedAt, _ = time.Parse(time.RFC3339, requestedAt)
+		if !result.Skipped && !utils.IsRetryable(result.Err) {
+			log.Printf("Dropping SQS message %s due to non-retryable error [%s]: %v", result.MessageId, utils.ErrorCodeOf(result.Err), result.Err)
+			utils.WebhookLog("ndns-tesseract: SQS PERMANENT FAILURE: %s", map[string]interface{}{
+				"messageId": result.MessageId,
+				"errorCode": utils.ErrorCodeOf(result.Err),
+				"error":     result.Err.Error(),
+			})
+			continue
 		}
 
-		// CrawlResult 파싱
-		if crawlResultMap, ok := bodyMap["crawlResult"].(map[string]interface{}); ok {
-			queueState.CrawlResult = &customTypes.CrawlResult{
-				Url:              getString(crawlResultMap, "url"),
-				FirstParagraph:   getString(crawlResultMap, "firstParagraph"),
-				LastParagraph:    getString(crawlResultMap, "lastParagraph"),
-				Content:          getString(crawlResultMap, "content"),
-				FirstImageUrl:    getString(crawlResultMap, "firstImageUrl"),
-				LastImageUrl:     getString(crawlResultMap, "lastImageUrl"),
-				FirstStickerUrl:  getString(crawlResultMap, "firstStickerUrl"),
-				SecondStickerUrl: getString(crawlResultMap, "secondStickerUrl"),
-				LastStickerUrl:   getString(crawlResultMap, "lastStickerUrl"),
+		log.Printf("SQS message %s failed [%s], reporting for retry: %v", result.MessageId, utils.ErrorCodeOf(result.Err), result.Err)
+		response.BatchItemFailures = append(response.BatchItemFailures, events.SQSBatchItemFailure{
+			ItemIdentifier: result.MessageId,
+		})
+	}
+
+	log.Printf("Processed SQS batch: %d records, %d failures reported", len(e.Records), len(response.BatchItemFailures))
+	return response, nil
+}
+
+// processSQSRecords는 제한된 수의 워커로 메시지를 동시에 처리하고 입력 순서대로 결과를 반환합니다.
+func processSQSRecords(ctx context.Context, records []events.SQSMessage, concurrency int, minRemaining time.Duration) []sqsRecordResult {
+	results := make([]sqsRecordResult, len(records))
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < min(concurrency, len(records)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results[i] = runSQSRecord(ctx, records[i], minRemaining)
 			}
+		}()
+	}
+
+	for i := range records {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	return results
+}
+
+// runSQSRecord는 남은 실행 시간을 확인한 뒤 메시지 하나를 처리합니다.
+func runSQSRecord(ctx context.Context, record events.SQSMessage, minRemaining time.Duration) sqsRecordResult {
+	result := sqsRecordResult{MessageId: record.MessageId}
+
+	if err := ctx.Err(); err != nil {
+		result.Err = fmt.Errorf("context done before processing: %w", err)
+		result.Skipped = true
+		return result
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		if remaining := time.Until(deadline); remaining < minRemaining {
+			result.Err = fmt.Errorf("not enough time remaining to process message: %s", remaining)
+			result.Skipped = true
+			return result
 		}
+	}
 
-		// 디버그 로깅
-		log.Printf("Parsed queueState from SQS message - ReqId: %s, JobId: %s, Position: %s, URL: %s",
-			queueState.ReqId,
-			queueState.JobId,
-			queueState.CurrentPosition,
-			queueState.CrawlResult.Url)
-		utils.WebhookLog("ndns-tesseract: SQS RECEIVED: %s", queueState.JobId)
-		result, err := services.HandleOcrWorkflow(ctx, queueState)
-		if err != nil {
-			log.Printf("Error processing record: %v", err)
-			continue
+	startedAt := time.Now()
+	result.Err = processSQSRecord(ctx, record)
+	result.Duration = time.Since(startedAt)
+	return result
+}
+
+// sqsConcurrency는 SQS_CONCURRENCY 환경 변수로 설정된 동시 처리 수를 반환합니다.
+func sqsConcurrency() int {
+	if raw := os.Getenv(sqsConcurrencyEnv); raw != "" {
+		if v, err := strconv.Atoi(raw); err == nil && v > 0 {
+			return v
 		}
-		log.Printf("Successfully processed record: %s", result.JobId)
+		log.Printf("WARNING: Invalid %s %q, using %d", sqsConcurrencyEnv, raw, defaultSQSConcurrency)
+	}
+	return defaultSQSConcurrency
+}
+
+// sqsMinRemaining은 새 메시지를 시작하기 위해 필요한 최소 남은 실행 시간을 반환합니다.
+func sqsMinRemaining() time.Duration {
+	if raw := os.Getenv(sqsMinRemainingMsEnv); raw != "" {
+		if v, err := strconv.Atoi(raw); err == nil && v >= 0 {
+			return time.Duration(v) * time.Millisecond
+		}
+		log.Printf("WARNING: Invalid %s %q, using %s", sqsMinRemainingMsEnv, raw, defaultSQSMinRemaining)
+	}
+	return defaultSQSMinRemaining
+}
+
+// processSQSRecord는 SQS 메시지 하나를 디코딩하고 OCR 워크플로우를 실행합니다.
+func processSQSRecord(ctx context.Context, record events.SQSMessage) error {
+	queueState, err := customTypes.DecodeOcrQueueStateSQS(record.Body, messageAttributeStrings(record.MessageAttributes))
+	if err != nil {
+		return queueStateError(err, "SQS message")
 	}
 
-	return utils.Response(nil, nil)
+	log.Printf("Decoded SQS message - ReqId: %s, JobId: %s, Position: %s, URL: %s",
+		queueState.ReqId,
+		queueState.JobId,
+		queueState.CurrentPosition,
+		queueState.CrawlUrl())
+	utils.WebhookLog("ndns-tesseract: SQS RECEIVED: %s", queueState.JobId)
+	result, err := ocrService.HandleOcrWorkflow(ctx, queueState)
+	if err != nil {
+		return err
+	}
+	log.Printf("Successfully processed record: %s", result.JobId)
+	return nil
 }
@@ -0,0 +1,249 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/ndns-dev/ndns-tesseract/src/services"
+	customTypes "github.com/ndns-dev/ndns-tesseract/src/types"
+	"github.com/ndns-dev/ndns-tesseract/src/utils"
+)
+
+// fakeOcrService는 jobId마다 정해 둔 오류를 반환하는 OCR 서비스입니다.
+// delay가 있으면 처리마다 그만큼 기다리고, 동시에 처리 중인 최대 메시지 수를 기록합니다.
+type fakeOcrService struct {
+	mu          sync.Mutex
+	errs        map[string]error // jobId -> HandleOcrWorkflow 오류
+	delay       time.Duration
+	calls       []string
+	inFlight    int
+	maxInFlight int
+}
+
+func (f *fakeOcrService) HandleOcrWorkflow(ctx context.Context, queueState customTypes.OcrQueueState) (*customTypes.OcrResult, error) {
+	f.mu.Lock()
+	f.calls = append(f.calls, queueState.JobId)
+	err := f.errs[queueState.JobId]
+	f.inFlight++
+	f.maxInFlight = max(f.maxInFlight, f.inFlight)
+	f.mu.Unlock()
+
+	time.Sleep(f.delay)
+
+	f.mu.Lock()
+	f.inFlight--
+	f.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	return &customTypes.OcrResult{JobId: queueState.JobId}, nil
+}
+
+// GetJobStatus는 SQS 처리에서 사용하지 않으므로 항상 JOB_NOT_FOUND를 반환합니다.
+func (f *fakeOcrService) GetJobStatus(ctx context.Context, jobId, position string) (*customTypes.JobStatusResponse, error) {
+	return nil, utils.Errorf(customTypes.ErrorCodeJobNotFound, "job %s not found", jobId)
+}
+
+// ListJobResults는 SQS 처리에서 사용하지 않으므로 항상 빈 페이지를 반환합니다.
+func (f *fakeOcrService) ListJobResults(ctx context.Context, jobId string, query services.ResultQuery) (*services.ResultPage, error) {
+	return &services.ResultPage{Results: []customTypes.OcrResult{}}, nil
+}
+
+// calledJobs는 처리한 jobId를 정렬해 반환합니다.
+func (f *fakeOcrService) calledJobs() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	jobs := append([]string(nil), f.calls...)
+	sort.Strings(jobs)
+	return jobs
+}
+
+// useOcrService는 테스트 동안 핸들러의 OCR 서비스를 바꿉니다.
+func useOcrService(t *testing.T, service ocrWorkflowService) {
+	t.Helper()
+	previous := ocrService
+	ocrService = service
+	t.Cleanup(func() { ocrService = previous })
+}
+
+// sqsRecord는 jobId 작업을 담은 SQS 메시지입니다. 메시지 ID는 "msg-" + jobId입니다.
+func sqsRecord(jobId string) events.SQSMessage {
+	return events.SQSMessage{
+		MessageId:   "msg-" + jobId,
+		EventSource: "aws:sqs",
+		Body:        fmt.Sprintf(`{"jobId":%q,"currentPosition":"FirstImageUrl","crawlResult":{"url":"https://blog/1","firstImageUrl":"https://img/1"}}`, jobId),
+	}
+}
+
+// failureIds는 응답의 BatchItemFailures를 정렬한 메시지 ID 목록으로 바꿉니다.
+func failureIds(t *testing.T, raw interface{}) []string {
+	t.Helper()
+	response, ok := raw.(events.SQSEventResponse)
+	if !ok {
+		t.Fatalf("response is %T, want events.SQSEventResponse", raw)
+	}
+	ids := []string{}
+	for _, failure := range response.BatchItemFailures {
+		ids = append(ids, failure.ItemIdentifier)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func TestHandleSQSEventBatchItemFailures(t *testing.T) {
+	service := &fakeOcrService{errs: map[string]error{
+		"retryable": errors.New("image server returned 503"),
+		"permanent": utils.Errorf(customTypes.ErrorCodeValidation, "invalid currentPosition: Middle"),
+		"wrapped":   fmt.Errorf("workflow failed: %w", utils.Errorf(customTypes.ErrorCodeAnalyzeApiRejected, "analyze API returned 400")),
+	}}
+	useOcrService(t, service)
+
+	malformed := events.SQSMessage{MessageId: "msg-malformed", EventSource: "aws:sqs", Body: "{not json"}
+	event := events.SQSEvent{Records: []events.SQSMessage{
+		sqsRecord("ok"), sqsRecord("retryable"), sqsRecord("permanent"), sqsRecord("wrapped"), malformed,
+	}}
+
+	raw, err := HandleSQSEvent(context.Background(), event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := failureIds(t, raw), []string{"msg-retryable"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("failures = %v, want %v", got, want)
+	}
+	if got, want := service.calledJobs(), []string{"ok", "permanent", "retryable", "wrapped"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("processed jobs = %v, want %v", got, want)
+	}
+}
+
+func TestHandleSQSEventEmptyBatch(t *testing.T) {
+	useOcrService(t, &fakeOcrService{})
+	raw, err := HandleSQSEvent(context.Background(), events.SQSEvent{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := failureIds(t, raw); len(got) != 0 {
+		t.Errorf("failures = %v, want none", got)
+	}
+}
+
+func TestHandleSQSEventConcurrency(t *testing.T) {
+	t.Setenv("SQS_CONCURRENCY", "3")
+	service := &fakeOcrService{errs: map[string]error{}, delay: 10 * time.Millisecond}
+	var records []events.SQSMessage
+	var wantFailures, wantJobs []string
+	for i := 0; i < 20; i++ {
+		jobId := fmt.Sprintf("job-%02d", i)
+		switch i % 3 {
+		case 1:
+			service.errs[jobId] = errors.New("temporary failure")
+			wantFailures = append(wantFailures, "msg-"+jobId)
+		case 2:
+			service.errs[jobId] = utils.Errorf(customTypes.ErrorCodeValidation, "bad request")
+		}
+		records = append(records, sqsRecord(jobId))
+		wantJobs = append(wantJobs, jobId)
+	}
+	useOcrService(t, service)
+
+	raw, err := HandleSQSEvent(context.Background(), events.SQSEvent{Records: records})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := failureIds(t, raw); !reflect.DeepEqual(got, wantFailures) {
+		t.Errorf("failures = %v, want %v", got, wantFailures)
+	}
+	if got := service.calledJobs(); !reflect.DeepEqual(got, wantJobs) {
+		t.Errorf("processed jobs = %v, want %v", got, wantJobs)
+	}
+	if service.maxInFlight != 3 {
+		t.Errorf("max in-flight = %d, want 3", service.maxInFlight)
+	}
+}
+
+func TestProcessSQSRecordsKeepsInputOrder(t *testing.T) {
+	service := &fakeOcrService{errs: map[string]error{"b": errors.New("failed")}}
+	useOcrService(t, service)
+	records := []events.SQSMessage{sqsRecord("a"), sqsRecord("b"), sqsRecord("c")}
+
+	results := processSQSRecords(context.Background(), records, 8, 0)
+	for i, result := range results {
+		if result.MessageId != records[i].MessageId {
+			t.Errorf("result %d is for %s, want %s", i, result.MessageId, records[i].MessageId)
+		}
+		if wantErr := records[i].MessageId == "msg-b"; (result.Err != nil) != wantErr {
+			t.Errorf("result %d err = %v", i, result.Err)
+		}
+	}
+}
+
+func TestHandleSQSEventSkipsWhenDeadlineIsNear(t *testing.T) {
+	t.Setenv("SQS_MIN_REMAINING_MS", "5000")
+	service := &fakeOcrService{errs: map[string]error{"permanent": utils.Errorf(customTypes.ErrorCodeValidation, "bad request")}}
+	useOcrService(t, service)
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	nearDeadline, cancelDeadline := context.WithTimeout(context.Background(), time.Second)
+	defer cancelDeadline()
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+	}{
+		{name: "deadline within minimum remaining time", ctx: nearDeadline},
+		{name: "context already done", ctx: cancelled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 건너뛴 메시지는 재시도하지 않을 오류가 예상되는 메시지라도 다시 전달되도록 실패로 보고합니다.
+			event := events.SQSEvent{Records: []events.SQSMessage{sqsRecord("ok"), sqsRecord("permanent")}}
+			raw, err := HandleSQSEvent(tt.ctx, event)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := failureIds(t, raw), []string{"msg-ok", "msg-permanent"}; !reflect.DeepEqual(got, want) {
+				t.Errorf("failures = %v, want %v", got, want)
+			}
+			if got := service.calledJobs(); len(got) != 0 {
+				t.Errorf("processed jobs = %v, want none", got)
+			}
+		})
+	}
+}
+
+func TestHandleSQSEventSkipsClaimedJobs(t *testing.T) {
+	ctx := context.Background()
+	store := services.NewMemoryJobStateStore(services.JobStateConfig{LeaseDuration: time.Minute, MaxAttempts: 3})
+	done, err := store.Claim(ctx, "done", "FirstImageUrl", "https://img/1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Complete(ctx, done, "https://img/1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Claim(ctx, "busy", "FirstImageUrl", "https://img/1"); err != nil {
+		t.Fatal(err)
+	}
+	useOcrService(t, services.NewOcrService(utils.NewFakeOcrEngine(""),
+		services.WithResultCache(nil),
+		services.WithJobStateStore(store),
+	))
+
+	// 이미 완료된 작업은 다시 처리하지 않고 삭제하며, 다른 워커가 처리 중인 작업은 리스가 끝난 뒤 다시 전달되도록 실패로 보고합니다.
+	// 두 메시지 모두 OCR을 실행하지 않으므로 이미지 URL에 접근하지 않습니다.
+	raw, err := HandleSQSEvent(ctx, events.SQSEvent{Records: []events.SQSMessage{sqsRecord("done"), sqsRecord("busy")}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := failureIds(t, raw), []string{"msg-busy"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("failures = %v, want %v", got, want)
+	}
+}
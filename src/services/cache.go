@@ -0,0 +1,136 @@
+package services
+
+import (
+	"context"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	customTypes "github.com/ndns-dev/ndns-tesseract/src/types"
+	"github.com/ndns-dev/ndns-tesseract/src/utils"
+)
+
+// OCR 결과 캐시 기본 설정
+const (
+	DefaultOcrCacheSize = 1000
+	DefaultOcrCacheTTL  = 7 * 24 * time.Hour
+)
+
+// OcrResultCache는 컨테이너 단위 LRU와 OcrResult 테이블을 차례로 조회하는 OCR 결과 캐시입니다.
+// LRU는 이미지 내용 해시와 처리 설정 키로, 테이블은 ImageUrl로 조회하며 두 경우 모두
+// ContentHash와 ProcessingKey가 일치하고 TTL 이내인 결과만 적중으로 처리합니다.
+// 같은 URL의 이미지가 바뀌거나, 전처리/OCR 옵션이 다르거나, 현재보다 낮은 파이프라인 버전이나
+// 다른 엔진 버전으로 만든 결과면 다시 OCR 합니다.
+type OcrResultCache struct {
+	lru   *utils.LRUCache[string, customTypes.OcrResult]
+	ttl   time.Duration
+	store ResultStore // nil이면 메모리 캐시만 사용
+}
+
+// NewOcrResultCache는 LRU 크기, TTL, 메모리 캐시에 없을 때 조회할 결과 저장소로 캐시를 생성합니다.
+// store가 nil이면 저장소를 조회하지 않습니다.
+func NewOcrResultCache(size int, ttl time.Duration, store ResultStore) *OcrResultCache {
+	return &OcrResultCache{
+		lru:   utils.NewLRUCache[string, customTypes.OcrResult](size),
+		ttl:   ttl,
+		store: store,
+	}
+}
+
+// NewOcrResultCacheFromEnv는 환경 변수 설정으로 캐시를 생성합니다.
+// OCR_CACHE_DISABLED=true면 nil을 반환합니다. OCR_CACHE_SIZE, OCR_CACHE_TTL(Go duration),
+// OCR_CACHE_TABLE_LOOKUP(기본 true)으로 동작을 조정할 수 있습니다. store는 테이블 조회에 사용할 결과 저장소입니다.
+func NewOcrResultCacheFromEnv(store ResultStore) *OcrResultCache {
+	if disabled, _ := strconv.ParseBool(os.Getenv("OCR_CACHE_DISABLED")); disabled {
+		return nil
+	}
+
+	size := DefaultOcrCacheSize
+	if raw := os.Getenv("OCR_CACHE_SIZE"); raw != "" {
+		if v, err := strconv.Atoi(raw); err == nil && v > 0 {
+			size = v
+		} else {
+			log.Printf("WARNING: Invalid OCR_CACHE_SIZE %q, using %d", raw, size)
+		}
+	}
+
+	ttl := DefaultOcrCacheTTL
+	if raw := os.Getenv("OCR_CACHE_TTL"); raw != "" {
+		if v, err := time.ParseDuration(raw); err == nil && v > 0 {
+			ttl = v
+		} else {
+			log.Printf("WARNING: Invalid OCR_CACHE_TTL %q, using %s", raw, ttl)
+		}
+	}
+
+	if raw := os.Getenv("OCR_CACHE_TABLE_LOOKUP"); raw != "" {
+		if v, err := strconv.ParseBool(raw); err == nil && !v {
+			store = nil
+		}
+	}
+
+	return NewOcrResultCache(size, ttl, store)
+}
+
+// Get은 이미지 URL과 내용 해시로 캐시된 결과를 찾습니다. engineVersion은 현재 OCR 엔진의 버전입니다.
+func (c *OcrResultCache) Get(ctx context.Context, imageUrl, contentHash, processingKey, engineVersion string) (*customTypes.OcrResult, bool) {
+	key := lruKey(contentHash, processingKey)
+	if cached, ok := c.lru.Get(key); ok {
+		if c.isFresh(cached, contentHash, processingKey, engineVersion) {
+			log.Printf("OCR cache hit (memory): %s", imageUrl)
+			return &cached, true
+		}
+		c.lru.Remove(key)
+	}
+
+	if c.store == nil {
+		return nil, false
+	}
+
+	stored, err := c.store.GetByImageUrl(ctx, imageUrl)
+	if err != nil {
+		log.Printf("WARNING: OCR cache table lookup failed for %s: %v", imageUrl, err)
+		return nil, false
+	}
+	if stored == nil || !c.isFresh(*stored, contentHash, processingKey, engineVersion) {
+		return nil, false
+	}
+
+	log.Printf("OCR cache hit (table): %s", imageUrl)
+	c.lru.Put(key, *stored)
+	return stored, true
+}
+
+// Put은 결과를 메모리 캐시에 저장합니다. 테이블 저장은 워크플로우에서 수행합니다.
+func (c *OcrResultCache) Put(result customTypes.OcrResult) {
+	if result.ContentHash == "" || result.Error != "" {
+		return
+	}
+	c.lru.Put(lruKey(result.ContentHash, result.ProcessingKey), result)
+}
+
+// lruKey는 메모리 캐시 키를 만듭니다.
+func lruKey(contentHash, processingKey string) string {
+	return contentHash + "|" + processingKey
+}
+
+// isFresh는 결과가 같은 이미지 내용과 처리 설정, 현재 파이프라인/엔진 버전에 대한 것이고 TTL 이내인지 확인합니다.
+func (c *OcrResultCache) isFresh(result customTypes.OcrResult, contentHash, processingKey, engineVersion string) bool {
+	if result.ContentHash == "" || result.ContentHash != contentHash || result.Error != "" {
+		return false
+	}
+	if result.ProcessingKey != processingKey {
+		return false
+	}
+	if result.PipelineVersion < customTypes.OcrPipelineVersion {
+		return false
+	}
+	if result.EngineVersion != "" && engineVersion != "" && result.EngineVersion != engineVersion {
+		return false
+	}
+	if result.ExpiresAt != nil && time.Now().After(*result.ExpiresAt) {
+		return false
+	}
+	return time.Since(result.ProcessedAt) <= c.ttl
+}
@@ -0,0 +1,101 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	customTypes "github.com/ndns-dev/ndns-tesseract/src/types"
+	"github.com/ndns-dev/ndns-tesseract/src/utils"
+)
+
+// cacheLookup은 OcrResultCache.Get에 전달하는 조회 조건입니다.
+type cacheLookup struct {
+	contentHash, processingKey, engineVersion string
+}
+
+func TestOcrResultCacheGet(t *testing.T) {
+	past := time.Now().Add(-time.Minute)
+	future := time.Now().Add(time.Hour)
+	fresh := customTypes.OcrResult{
+		ImageUrl:        "https://img/1",
+		ContentHash:     "hash-1",
+		ProcessingKey:   "key",
+		EngineVersion:   "tesseract 5.3.4",
+		PipelineVersion: customTypes.OcrPipelineVersion,
+		ProcessedAt:     time.Now(),
+		ExpiresAt:       &future,
+	}
+	current := cacheLookup{contentHash: "hash-1", processingKey: "key", engineVersion: "tesseract 5.3.4"}
+
+	tests := []struct {
+		name    string
+		modify  func(result *customTypes.OcrResult)
+		lookup  cacheLookup
+		wantHit bool
+	}{
+		{name: "fresh", lookup: current, wantHit: true},
+		{name: "unknown engine version", lookup: cacheLookup{contentHash: "hash-1", processingKey: "key"}, wantHit: true},
+		{name: "image changed", lookup: cacheLookup{contentHash: "hash-2", processingKey: "key", engineVersion: "tesseract 5.3.4"}},
+		{name: "different options", lookup: cacheLookup{contentHash: "hash-1", processingKey: "other", engineVersion: "tesseract 5.3.4"}},
+		{name: "different engine", lookup: cacheLookup{contentHash: "hash-1", processingKey: "key", engineVersion: "tesseract 5.4.0"}},
+		{name: "older pipeline", lookup: current, modify: func(r *customTypes.OcrResult) { r.PipelineVersion = customTypes.OcrPipelineVersion - 1 }},
+		{name: "expired", lookup: current, modify: func(r *customTypes.OcrResult) { r.ExpiresAt = &past }},
+		{name: "older than cache TTL", lookup: current, modify: func(r *customTypes.OcrResult) { r.ProcessedAt = time.Now().Add(-2 * time.Hour) }},
+		{name: "failed result", lookup: current, modify: func(r *customTypes.OcrResult) { r.Error = "failed" }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := fresh
+			if tt.modify != nil {
+				tt.modify(&result)
+			}
+
+			// 메모리 캐시와 결과 저장소 조회 모두 같은 규칙을 따라야 합니다.
+			memory := NewOcrResultCache(10, time.Hour, nil)
+			memory.Put(result)
+			if _, hit := memory.Get(context.Background(), result.ImageUrl, tt.lookup.contentHash, tt.lookup.processingKey, tt.lookup.engineVersion); hit != tt.wantHit {
+				t.Errorf("memory hit = %t, want %t", hit, tt.wantHit)
+			}
+
+			store := NewMemoryResultStore()
+			if err := store.Save(context.Background(), result); err != nil {
+				t.Fatal(err)
+			}
+			table := NewOcrResultCache(10, time.Hour, store)
+			if _, hit := table.Get(context.Background(), result.ImageUrl, tt.lookup.contentHash, tt.lookup.processingKey, tt.lookup.engineVersion); hit != tt.wantHit {
+				t.Errorf("table hit = %t, want %t", hit, tt.wantHit)
+			}
+		})
+	}
+}
+
+func TestRecognizePositionUsesCache(t *testing.T) {
+	server := newImageServer(t)
+	engine := utils.NewFakeOcrEngine("소정의 원고료를 받아 작성")
+	service := NewOcrService(engine, WithResultCache(NewOcrResultCache(10, time.Hour, nil)))
+
+	// 같은 내용의 이미지는 다른 작업과 위치에서도 한 번만 인식합니다.
+	first, err := service.recognizePosition(context.Background(), customTypes.OcrQueueState{JobId: "job-1"}, customTypes.OcrPositionFirstImage, server.URL+"/ok/same")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := service.recognizePosition(context.Background(), customTypes.OcrQueueState{JobId: "job-2"}, customTypes.OcrPositionLastImage, server.URL+"/ok/same")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := len(engine.Calls()); got != 1 {
+		t.Errorf("engine calls = %d, want 1", got)
+	}
+	if first.CacheHit || !second.CacheHit {
+		t.Errorf("CacheHit = %t, %t, want false, true", first.CacheHit, second.CacheHit)
+	}
+	if second.JobId != "job-2" || second.Position != customTypes.OcrPositionLastImage {
+		t.Errorf("cached result = %s/%s, want it re-labelled for job-2/%s", second.JobId, second.Position, customTypes.OcrPositionLastImage)
+	}
+	if !second.Disclosure.Detected {
+		t.Error("cached result should be checked for disclosure again")
+	}
+}
@@ -0,0 +1,137 @@
+package services
+
+import (
+	"bufio"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	customTypes "github.com/ndns-dev/ndns-tesseract/src/types"
+	"github.com/ndns-dev/ndns-tesseract/src/utils"
+)
+
+// DefaultDisclosurePhrases는 기본 협찬/광고 고지 문구 사전입니다.
+var DefaultDisclosurePhrases = []string{
+	"소정의 원고료",
+	"원고료를 지원받아",
+	"제품을 제공받아",
+	"제품을 무상으로 제공받아",
+	"업체로부터 제공받아",
+	"지원받아 작성",
+	"경제적 대가",
+	"유료광고",
+	"체험단",
+	"협찬",
+}
+
+const (
+	// DefaultDisclosureThreshold는 탐지로 판정하는 최소 일치 점수입니다.
+	DefaultDisclosureThreshold = 0.8
+	// minFuzzyJamoLength보다 짧은 문구는 오탐을 막기 위해 정확히 일치해야 합니다.
+	minFuzzyJamoLength = 8
+)
+
+// disclosurePhrase는 사전 문구와 미리 분해해 둔 자모 시퀀스입니다.
+type disclosurePhrase struct {
+	text string
+	jamo []rune
+}
+
+// DisclosureDetector는 OCR 오류에 강한 자모 단위 퍼지 매칭으로 고지 문구를 탐지합니다.
+type DisclosureDetector struct {
+	phrases   []disclosurePhrase
+	threshold float64
+}
+
+// NewDisclosureDetector는 문구 사전과 임계값으로 탐지기를 생성합니다.
+func NewDisclosureDetector(phrases []string, threshold float64) *DisclosureDetector {
+	d := &DisclosureDetector{threshold: threshold}
+	for _, phrase := range phrases {
+		phrase = strings.TrimSpace(phrase)
+		if phrase == "" {
+			continue
+		}
+		d.phrases = append(d.phrases, disclosurePhrase{
+			text: phrase,
+			jamo: utils.NewJamoSequence(phrase).Jamo,
+		})
+	}
+	return d
+}
+
+// NewDisclosureDetectorFromEnv는 환경 변수 설정으로 탐지기를 생성합니다.
+// DISCLOSURE_PHRASES_FILE(줄 단위) 또는 DISCLOSURE_PHRASES(쉼표 구분)로 사전을 교체하고,
+// DISCLOSURE_MATCH_THRESHOLD로 임계값을 조정할 수 있습니다.
+func NewDisclosureDetectorFromEnv() *DisclosureDetector {
+	phrases := DefaultDisclosurePhrases
+	if path := os.Getenv("DISCLOSURE_PHRASES_FILE"); path != "" {
+		loaded, err := loadPhrasesFile(path)
+		if err != nil {
+			log.Printf("WARNING: Failed to load disclosure phrases from %s, using defaults: %v", path, err)
+		} else {
+			phrases = loaded
+		}
+	} else if raw := os.Getenv("DISCLOSURE_PHRASES"); raw != "" {
+		phrases = strings.Split(raw, ",")
+	}
+
+	threshold := DefaultDisclosureThreshold
+	if raw := os.Getenv("DISCLOSURE_MATCH_THRESHOLD"); raw != "" {
+		if v, err := strconv.ParseFloat(raw, 64); err == nil && v > 0 && v <= 1 {
+			threshold = v
+		} else {
+			log.Printf("WARNING: Invalid DISCLOSURE_MATCH_THRESHOLD %q, using %.2f", raw, threshold)
+		}
+	}
+	return NewDisclosureDetector(phrases, threshold)
+}
+
+// Detect는 텍스트에서 가장 잘 일치하는 고지 문구를 찾아 판정 결과를 반환합니다.
+func (d *DisclosureDetector) Detect(text string) *customTypes.DisclosureVerdict {
+	verdict := &customTypes.DisclosureVerdict{}
+	seq := utils.NewJamoSequence(text)
+	if len(seq.Jamo) == 0 {
+		return verdict
+	}
+
+	for _, phrase := range d.phrases {
+		distance, start, end := utils.SubstringEditDistance(phrase.jamo, seq.Jamo)
+		if len(phrase.jamo) < minFuzzyJamoLength && distance > 0 {
+			continue
+		}
+
+		score := 1 - float64(distance)/float64(len(phrase.jamo))
+		if score <= verdict.Score {
+			continue
+		}
+		verdict.Score = score
+		verdict.MatchedPhrase = phrase.text
+		if end > start {
+			verdict.MatchedText = string(seq.Runes[seq.Origins[start] : seq.Origins[end-1]+1])
+		}
+	}
+
+	verdict.Detected = verdict.Score >= d.threshold
+	return verdict
+}
+
+// loadPhrasesFile은 줄 단위 문구 파일을 읽습니다. 빈 줄과 #으로 시작하는 줄은 무시합니다.
+func loadPhrasesFile(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var phrases []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		phrases = append(phrases, line)
+	}
+	return phrases, scanner.Err()
+}
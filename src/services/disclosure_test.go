@@ -0,0 +1,55 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDisclosureDetectorDetect(t *testing.T) {
+	detector := NewDisclosureDetector(DefaultDisclosurePhrases, DefaultDisclosureThreshold)
+
+	tests := []struct {
+		name       string
+		text       string
+		wantDetect bool
+		wantPhrase string
+	}{
+		{name: "exact phrase", text: "이 글은 소정의 원고료를 받아 작성했습니다", wantDetect: true, wantPhrase: "소정의 원고료"},
+		{name: "spacing and punctuation", text: "제품을, 무상으로제공 받아 작성", wantDetect: true, wantPhrase: "제품을 무상으로 제공받아"},
+		{name: "ocr vowel error", text: "업체로부터 제공받0ㅏ 작성", wantDetect: true, wantPhrase: "업체로부터 제공받아"},
+		{name: "short phrase exact", text: "#협찬 #맛집", wantDetect: true, wantPhrase: "협찬"},
+		{name: "short phrase with one error", text: "헙찬 받은 글 아님", wantDetect: false},
+		{name: "unrelated text", text: "오늘 다녀온 카페 후기입니다", wantDetect: false},
+		{name: "empty", text: "", wantDetect: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verdict := detector.Detect(tt.text)
+			if verdict.Detected != tt.wantDetect {
+				t.Fatalf("Detected = %t (score %.2f, phrase %q), want %t", verdict.Detected, verdict.Score, verdict.MatchedPhrase, tt.wantDetect)
+			}
+			if tt.wantPhrase != "" && verdict.MatchedPhrase != tt.wantPhrase {
+				t.Errorf("MatchedPhrase = %q, want %q", verdict.MatchedPhrase, tt.wantPhrase)
+			}
+			if verdict.Detected && verdict.MatchedText == "" {
+				t.Error("MatchedText should be set when detected")
+			}
+		})
+	}
+}
+
+func TestLoadPhrasesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "phrases.txt")
+	if err := os.WriteFile(path, []byte("# 주석\n협찬\n\n  광고 포함  \n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	phrases, err := loadPhrasesFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(phrases) != 2 || phrases[0] != "협찬" || phrases[1] != "광고 포함" {
+		t.Errorf("phrases = %q", phrases)
+	}
+}
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	customTypes "github.com/ndns-dev/ndns-tesseract/src/types"
+	"github.com/ndns-dev/ndns-tesseract/src/utils"
+)
+
+// requireDynamoDBLocal은 DYNAMODB_ENDPOINT가 없으면 테스트를 건너뛰고, 있으면 테이블을 준비합니다.
+// make test-integration으로 DynamoDB Local에 대해 실행합니다.
+func requireDynamoDBLocal(t *testing.T) context.Context {
+	t.Helper()
+	if os.Getenv("DYNAMODB_ENDPOINT") == "" {
+		t.Skip("DYNAMODB_ENDPOINT is not set; run make test-integration against DynamoDB Local")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	t.Cleanup(cancel)
+	if err := EnsureTables(ctx); err != nil {
+		t.Fatal(err)
+	}
+	return ctx
+}
+
+func TestDynamoJobStateStoreIntegration(t *testing.T) {
+	ctx := requireDynamoDBLocal(t)
+	store := NewDynamoJobStateStore(customTypes.OcrQueueStatusTableName, JobStateConfig{LeaseDuration: time.Minute, MaxAttempts: 3})
+	jobId := "integration-" + utils.NewRequestId()
+
+	lease, err := store.Claim(ctx, jobId, "FirstImageUrl", "https://img/1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = store.Claim(ctx, jobId, "FirstImageUrl", "https://img/1")
+	wantCode(t, err, customTypes.ErrorCodeJobInProgress)
+
+	if err := store.Fail(ctx, lease, utils.Errorf(customTypes.ErrorCodeImageUnavailable, "503")); err != nil {
+		t.Fatal(err)
+	}
+	lease, err = store.Claim(ctx, jobId, "FirstImageUrl", "https://img/1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lease.Attempt != 2 {
+		t.Errorf("Attempt = %d, want 2", lease.Attempt)
+	}
+	if err := store.Complete(ctx, lease, "https://img/1"); err != nil {
+		t.Fatal(err)
+	}
+	_, err = store.Claim(ctx, jobId, "FirstImageUrl", "https://img/1")
+	wantCode(t, err, customTypes.ErrorCodeJobDuplicate)
+
+	states, err := store.List(ctx, jobId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(states) != 1 || states[0].Status != customTypes.JobStatusCompleted || states[0].Attempts != 2 {
+		t.Errorf("states = %+v", states)
+	}
+}
+
+func TestDynamoResultStoreIntegration(t *testing.T) {
+	ctx := requireDynamoDBLocal(t)
+	store := NewDynamoResultStore(customTypes.OcrResultTableName)
+	jobId := "integration-" + utils.NewRequestId()
+
+	for _, position := range []customTypes.OcrPosition{customTypes.OcrPositionLastImage, customTypes.OcrPositionFirstImage} {
+		result := customTypes.OcrResult{
+			ImageUrl:        "https://img/" + jobId + "/" + string(position),
+			JobId:           jobId,
+			Position:        position,
+			PipelineVersion: customTypes.OcrPipelineVersion,
+			ProcessedAt:     time.Now(),
+		}
+		if err := store.Save(ctx, result); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stale := customTypes.OcrResult{
+		ImageUrl:        "https://img/" + jobId + "/" + string(customTypes.OcrPositionFirstImage),
+		JobId:           jobId,
+		Position:        customTypes.OcrPositionFirstImage,
+		PipelineVersion: customTypes.OcrPipelineVersion - 1,
+	}
+	if err := store.Save(ctx, stale); !errors.Is(err, ErrStaleResult) {
+		t.Fatalf("err = %v, want ErrStaleResult", err)
+	}
+	newerEngine := stale
+	newerEngine.PipelineVersion = customTypes.OcrPipelineVersion
+	newerEngine.EngineVersion = "tesseract 5.10.0"
+	if err := store.Save(ctx, newerEngine); err != nil {
+		t.Fatal(err)
+	}
+	olderEngine := newerEngine
+	olderEngine.EngineVersion = "tesseract 5.3.4"
+	if err := store.Save(ctx, olderEngine); !errors.Is(err, ErrStaleResult) {
+		t.Fatalf("err = %v, want ErrStaleResult for an older engine", err)
+	}
+
+	urls, pages := listAll(t, store, jobId, ResultQuery{Limit: 1})
+	if len(urls) != 2 || pages < 2 {
+		t.Errorf("urls = %v over %d pages", urls, pages)
+	}
+}
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	customTypes "github.com/ndns-dev/ndns-tesseract/src/types"
+	"github.com/ndns-dev/ndns-tesseract/src/utils"
+)
+
+// ProcessOcrJob은 CrawlResult의 비어 있지 않은 모든 이미지 위치를 OcrPositionOrder 순서로 처리합니다.
+// 협찬 문구가 탐지되면 남은 위치는 건너뜁니다.
+// 재시도하면 성공할 수 있는 오류로 실패한 위치가 있으면 결과에 빈 위치를 남기지 않도록 작업 전체를 그 오류로 실패시키고,
+// 재시도해도 성공할 수 없는 오류로 실패한 위치는 요약에 오류를 기록하고 건너뜁니다.
+// Summary는 판정에 사용된 위치(탐지된 위치 또는 마지막으로 성공한 위치)의 OcrResult에 처리한 모든 위치의 요약을
+// Positions로 붙인 것이고, Results는 저장할 위치별 OcrResult입니다.
+func (s *OcrService) ProcessOcrJob(ctx context.Context, queueState customTypes.OcrQueueState) (*customTypes.OcrJobResult, error) {
+	if err := validateQueueState(queueState, customTypes.OcrJobModeAllPositions); err != nil {
+		return nil, err
+	}
+
+	var decisive *customTypes.OcrResult
+	var results []customTypes.OcrResult
+	var positions []customTypes.OcrPositionResult
+	var lastErr error
+
+	for _, position := range customTypes.OcrPositionOrder {
+		imageUrl := queueState.CrawlResult.GetImageUrlByPosition(position)
+		if imageUrl == "" {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("job %s interrupted before %s: %w", queueState.JobId, position, err)
+		}
+
+		log.Printf("Processing job %s position %s: %s", queueState.JobId, position, imageUrl)
+		result, err := s.recognizePosition(ctx, queueState, position, imageUrl)
+		if err != nil {
+			log.Printf("Failed to process job %s position %s: %v", queueState.JobId, position, err)
+			if utils.IsRetryable(err) {
+				return nil, fmt.Errorf("job %s failed at %s: %w", queueState.JobId, position, err)
+			}
+			lastErr = err
+			positions = append(positions, customTypes.OcrPositionResult{
+				Position: position,
+				ImageUrl: imageUrl,
+				Error:    err.Error(),
+			})
+			continue
+		}
+
+		positions = append(positions, customTypes.OcrPositionResult{
+			Position:       position,
+			ImageUrl:       imageUrl,
+			OcrText:        result.OcrText,
+			MeanConfidence: result.MeanConfidence,
+			Disclosure:     result.Disclosure,
+		})
+		results = append(results, *result)
+		decisive = result
+
+		if result.Disclosure != nil && result.Disclosure.Detected {
+			log.Printf("Disclosure found for job %s at %s, skipping remaining positions", queueState.JobId, position)
+			break
+		}
+	}
+
+	if len(positions) == 0 {
+		return nil, utils.Errorf(customTypes.ErrorCodeValidation, "no image URL found in crawlResult for job: %s", queueState.JobId)
+	}
+	if decisive == nil {
+		return nil, fmt.Errorf("all positions failed for job %s: %w", queueState.JobId, lastErr)
+	}
+
+	summary := *decisive
+	summary.Positions = positions
+	return &customTypes.OcrJobResult{Summary: summary, Results: results}, nil
+}
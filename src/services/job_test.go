@@ -0,0 +1,276 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"image"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+
+	customTypes "github.com/ndns-dev/ndns-tesseract/src/types"
+	"github.com/ndns-dev/ndns-tesseract/src/utils"
+)
+
+// sequenceEngine은 호출 순서대로 texts의 문장을 인식 결과로 반환하는 테스트 엔진입니다.
+// 문장을 모두 쓰면 마지막 문장을 반복합니다.
+type sequenceEngine struct {
+	mu    sync.Mutex
+	texts []string
+	calls int
+}
+
+func (e *sequenceEngine) Name() string {
+	return "sequence"
+}
+
+func (e *sequenceEngine) Version(ctx context.Context) string {
+	return "sequence 1"
+}
+
+func (e *sequenceEngine) Recognize(ctx context.Context, imageBytes []byte, options customTypes.OcrOptions) (*customTypes.OcrEngineResult, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	text := e.texts[min(e.calls, len(e.texts)-1)]
+	e.calls++
+	lines := utils.BuildLinesFromText(text, 90)
+	return &customTypes.OcrEngineResult{
+		Engine:         e.Name(),
+		Text:           utils.JoinLineTexts(lines),
+		Lines:          lines,
+		MeanConfidence: utils.MeanWordConfidence(lines),
+	}, nil
+}
+
+// testImagePNG는 이름마다 내용이 다른 작은 PNG 이미지를 생성합니다.
+func testImagePNG(name string) []byte {
+	img := image.NewGray(image.Rect(0, 0, 4, 4))
+	sum := sha256.Sum256([]byte(name))
+	copy(img.Pix, sum[:])
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		panic(err)
+	}
+	return buf.Bytes()
+}
+
+// newImageServer는 /ok/* 경로는 경로마다 다른 PNG 이미지로, /missing은 404, /flaky는 503으로 응답하는 서버입니다.
+func newImageServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/missing":
+			http.NotFound(w, r)
+		case "/flaky":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			w.Header().Set("Content-Type", "image/png")
+			w.Write(testImagePNG(r.URL.Path))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// noRetryDownloader는 재시도 대기 없이 첫 실패를 반환하는 다운로더입니다.
+func noRetryDownloader() *utils.ImageDownloader {
+	downloader := utils.NewImageDownloader()
+	downloader.MaxRetries = 0
+	return downloader
+}
+
+func TestProcessOcrJob(t *testing.T) {
+	server := newImageServer(t)
+
+	tests := []struct {
+		name          string
+		crawl         customTypes.CrawlResult
+		wantErr       bool
+		wantCode      customTypes.ErrorCode
+		wantResults   []customTypes.OcrPosition
+		wantPositions int
+		wantDecisive  customTypes.OcrPosition
+	}{
+		{
+			name: "all positions succeed",
+			crawl: customTypes.CrawlResult{
+				FirstImageUrl:   server.URL + "/ok/first",
+				FirstStickerUrl: server.URL + "/ok/sticker",
+				LastImageUrl:    server.URL + "/ok/last",
+			},
+			wantResults:   []customTypes.OcrPosition{customTypes.OcrPositionFirstImage, customTypes.OcrPositionFirstSticker, customTypes.OcrPositionLastImage},
+			wantPositions: 3,
+			wantDecisive:  customTypes.OcrPositionLastImage,
+		},
+		{
+			name: "missing image is skipped",
+			crawl: customTypes.CrawlResult{
+				FirstImageUrl: server.URL + "/missing",
+				LastImageUrl:  server.URL + "/ok/last",
+			},
+			wantResults:   []customTypes.OcrPosition{customTypes.OcrPositionLastImage},
+			wantPositions: 2,
+			wantDecisive:  customTypes.OcrPositionLastImage,
+		},
+		{
+			name: "retryable failure fails the job",
+			crawl: customTypes.CrawlResult{
+				FirstImageUrl: server.URL + "/ok/first",
+				LastImageUrl:  server.URL + "/flaky",
+			},
+			wantErr:  true,
+			wantCode: customTypes.ErrorCodeImageUnavailable,
+		},
+		{
+			name: "all positions missing",
+			crawl: customTypes.CrawlResult{
+				FirstImageUrl: server.URL + "/missing",
+			},
+			wantErr:  true,
+			wantCode: customTypes.ErrorCodeImageNotFound,
+		},
+		{
+			name:     "no images",
+			crawl:    customTypes.CrawlResult{},
+			wantErr:  true,
+			wantCode: customTypes.ErrorCodeValidation,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewOcrService(utils.NewFakeOcrEngine("오늘 다녀온 카페 후기입니다"), WithResultCache(nil), WithImageDownloader(noRetryDownloader()))
+			crawl := tt.crawl
+			job, err := service.ProcessOcrJob(context.Background(), customTypes.OcrQueueState{
+				JobId:       "job-1",
+				Mode:        customTypes.OcrJobModeAllPositions,
+				CrawlResult: &crawl,
+			})
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ProcessOcrJob() error = nil, want error")
+				}
+				if got := utils.ErrorCodeOf(err); got != tt.wantCode {
+					t.Errorf("error code = %q (%v), want %q", got, err, tt.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ProcessOcrJob() error = %v", err)
+			}
+
+			if len(job.Results) != len(tt.wantResults) {
+				t.Fatalf("Results = %d, want %d", len(job.Results), len(tt.wantResults))
+			}
+			for i, result := range job.Results {
+				if result.Position != tt.wantResults[i] {
+					t.Errorf("Results[%d].Position = %s, want %s", i, result.Position, tt.wantResults[i])
+				}
+				if result.ImageUrl != tt.crawl.GetImageUrlByPosition(result.Position) {
+					t.Errorf("Results[%d].ImageUrl = %s, want the position's image URL", i, result.ImageUrl)
+				}
+			}
+			if len(job.Summary.Positions) != tt.wantPositions {
+				t.Errorf("Summary.Positions = %d, want %d", len(job.Summary.Positions), tt.wantPositions)
+			}
+			if job.Summary.Position != tt.wantDecisive {
+				t.Errorf("Summary.Position = %s, want %s", job.Summary.Position, tt.wantDecisive)
+			}
+		})
+	}
+}
+
+func TestProcessOcrJobStopsAtDisclosure(t *testing.T) {
+	server := newImageServer(t)
+	engine := &sequenceEngine{texts: []string{"오늘 다녀온 카페 후기입니다", "소정의 원고료를 받아 작성", "마지막 이미지"}}
+	service := NewOcrService(engine, WithResultCache(nil), WithImageDownloader(noRetryDownloader()))
+
+	job, err := service.ProcessOcrJob(context.Background(), customTypes.OcrQueueState{
+		JobId: "job-1",
+		Mode:  customTypes.OcrJobModeAllPositions,
+		CrawlResult: &customTypes.CrawlResult{
+			FirstImageUrl:   server.URL + "/ok/first",
+			FirstStickerUrl: server.URL + "/ok/sticker",
+			LastImageUrl:    server.URL + "/ok/last",
+		},
+	})
+	if err != nil {
+		t.Fatalf("ProcessOcrJob() error = %v", err)
+	}
+	if !job.Summary.Disclosure.Detected || job.Summary.Position != customTypes.OcrPositionFirstSticker {
+		t.Errorf("Summary = %s detected=%t, want %s detected", job.Summary.Position, job.Summary.Disclosure.Detected, customTypes.OcrPositionFirstSticker)
+	}
+	if len(job.Results) != 2 {
+		t.Errorf("Results = %d, want 2 (processing stops after the disclosure)", len(job.Results))
+	}
+}
+
+func TestProcessOcrValidation(t *testing.T) {
+	server := newImageServer(t)
+	crawlResult := &customTypes.CrawlResult{Url: "https://blog/1", FirstImageUrl: server.URL + "/ok/first"}
+
+	tests := []struct {
+		name       string
+		allPos     bool
+		queueState customTypes.OcrQueueState
+		wantFields []string
+	}{
+		{
+			name:       "single without position",
+			queueState: customTypes.OcrQueueState{JobId: "job-1", CrawlResult: crawlResult},
+			wantFields: []string{"currentPosition"},
+		},
+		{
+			name:       "single with unknown position and bad options",
+			queueState: customTypes.OcrQueueState{JobId: "job-1", CurrentPosition: "Middle", CrawlResult: crawlResult, Ocr: &customTypes.OcrOptions{Psm: 99}},
+			wantFields: []string{"currentPosition", "ocr.psm"},
+		},
+		{
+			name:       "job without jobId and crawlResult",
+			allPos:     true,
+			queueState: customTypes.OcrQueueState{},
+			wantFields: []string{"jobId", "crawlResult"},
+		},
+		{
+			name:       "job ignores currentPosition requirement",
+			allPos:     true,
+			queueState: customTypes.OcrQueueState{JobId: "job-1", CrawlResult: crawlResult, Preprocess: &customTypes.PreprocessOptions{Crop: "diagonal"}},
+			wantFields: []string{"preprocess.crop"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := &sequenceEngine{texts: []string{"텍스트"}}
+			service := NewOcrService(engine, WithResultCache(nil), WithImageDownloader(noRetryDownloader()))
+			var err error
+			if tt.allPos {
+				_, err = service.ProcessOcrJob(context.Background(), tt.queueState)
+			} else {
+				_, err = service.ProcessOcrRequest(context.Background(), tt.queueState)
+			}
+			if got := utils.ErrorCodeOf(err); got != customTypes.ErrorCodeValidation {
+				t.Fatalf("error code = %q (%v), want %q", got, err, customTypes.ErrorCodeValidation)
+			}
+			var fieldErrs customTypes.FieldErrors
+			if !errors.As(err, &fieldErrs) {
+				t.Fatalf("error %v carries no field errors", err)
+			}
+			var fields []string
+			for _, fieldErr := range fieldErrs {
+				fields = append(fields, fieldErr.Field)
+			}
+			if !reflect.DeepEqual(fields, tt.wantFields) {
+				t.Errorf("fields = %v, want %v", fields, tt.wantFields)
+			}
+			if engine.calls != 0 {
+				t.Errorf("engine ran for an invalid request")
+			}
+		})
+	}
+}
@@ -0,0 +1,476 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	ddbTypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	customTypes "github.com/ndns-dev/ndns-tesseract/src/types"
+	"github.com/ndns-dev/ndns-tesseract/src/utils"
+)
+
+// 작업 상태 기본 설정
+const (
+	DefaultJobLeaseDuration = 16 * time.Minute // 기한 없는 호출의 리스 길이 (Lambda 최대 제한 시간 15분보다 길게)
+	DefaultJobMaxAttempts   = 5
+	JobLeaseMargin          = 30 * time.Second // 호출 기한 뒤 상태 기록에 필요한 여유 시간
+)
+
+// JobLease는 Claim으로 잡은 작업/위치의 리스입니다. Complete와 Fail은 같은 Owner일 때만 반영됩니다.
+type JobLease struct {
+	JobId     string
+	Position  string
+	Owner     string
+	Attempt   int
+	ExpiresAt time.Time
+}
+
+// JobStateStore는 OcrQueueStatus 상태 전이(claim → PROCESSING → COMPLETED/FAILED)를 원자적으로 기록합니다.
+// 같은 JobId+position이 여러 번 전달되어도 한 워커만 OCR을 실행하도록 합니다.
+type JobStateStore interface {
+	// Claim은 작업/위치의 리스를 잡습니다. 이미 완료되었거나 재시도 한도를 넘었으면 JOB_ALREADY_PROCESSED,
+	// 다른 워커의 리스가 유효하면 JOB_IN_PROGRESS 코드의 오류를 반환합니다.
+	Claim(ctx context.Context, jobId, position, imageUrl string) (*JobLease, error)
+	// Complete는 리스를 가진 작업을 COMPLETED로 바꿉니다. imageUrl은 결과가 저장된 이미지 URL입니다
+	// (ALL_POSITIONS 작업은 판정에 사용된 위치의 URL).
+	Complete(ctx context.Context, lease *JobLease, imageUrl string) error
+	// Fail은 리스를 가진 작업을 FAILED로 바꾸고 오류 코드와 재시도 가능 여부를 기록합니다.
+	Fail(ctx context.Context, lease *JobLease, cause error) error
+	// Get은 작업/위치의 상태를 조회합니다. 없으면 nil을 반환합니다.
+	Get(ctx context.Context, jobId, position string) (*customTypes.OcrJobState, error)
+	// List는 작업의 모든 위치 상태를 position 순으로 조회합니다.
+	List(ctx context.Context, jobId string) ([]customTypes.OcrJobState, error)
+}
+
+// JobStateConfig는 리스 길이와 최대 시도 횟수입니다.
+// LeaseDuration은 ctx에 기한이 없을 때만 사용합니다.
+type JobStateConfig struct {
+	LeaseDuration time.Duration
+	MaxAttempts   int
+}
+
+// leaseDuration은 now부터의 리스 길이입니다. ctx에 기한이 있으면(Lambda 호출) 남은 시간에 JobLeaseMargin을 더해
+// 호출이 끝나기 전에 리스가 만료되어 다른 워커가 같은 작업을 잡는 일이 없게 합니다.
+func (c JobStateConfig) leaseDuration(ctx context.Context, now time.Time) time.Duration {
+	if deadline, ok := ctx.Deadline(); ok {
+		return max(deadline.Sub(now), 0) + JobLeaseMargin
+	}
+	return c.LeaseDuration
+}
+
+// jobStateConfigFromEnv는 JOB_LEASE_DURATION(Go duration, 기한 없는 호출용)과 JOB_MAX_ATTEMPTS로 설정을 만듭니다.
+func jobStateConfigFromEnv() JobStateConfig {
+	config := JobStateConfig{LeaseDuration: DefaultJobLeaseDuration, MaxAttempts: DefaultJobMaxAttempts}
+	if raw := os.Getenv("JOB_LEASE_DURATION"); raw != "" {
+		if v, err := time.ParseDuration(raw); err == nil && v > 0 {
+			config.LeaseDuration = v
+		} else {
+			log.Printf("WARNING: Invalid JOB_LEASE_DURATION %q, using %s", raw, config.LeaseDuration)
+		}
+	}
+	if raw := os.Getenv("JOB_MAX_ATTEMPTS"); raw != "" {
+		if v, err := strconv.Atoi(raw); err == nil && v > 0 {
+			config.MaxAttempts = v
+		} else {
+			log.Printf("WARNING: Invalid JOB_MAX_ATTEMPTS %q, using %d", raw, config.MaxAttempts)
+		}
+	}
+	return config
+}
+
+// NewJobStateStoreFromEnv는 JOB_STATE_STORE 설정으로 작업 상태 저장소를 생성합니다.
+// dynamodb(기본값), memory(로컬 개발용), none(중복 처리 방지 안 함, nil 반환)을 지원합니다.
+func NewJobStateStoreFromEnv() JobStateStore {
+	config := jobStateConfigFromEnv()
+	switch kind := strings.ToLower(os.Getenv("JOB_STATE_STORE")); kind {
+	case "", "dynamodb":
+		return NewDynamoJobStateStore(customTypes.OcrQueueStatusTableName, config)
+	case "memory":
+		return NewMemoryJobStateStore(config)
+	case "none":
+		return nil
+	default:
+		log.Printf("WARNING: Unknown JOB_STATE_STORE %q, using dynamodb", kind)
+		return NewDynamoJobStateStore(customTypes.OcrQueueStatusTableName, config)
+	}
+}
+
+// claimRejection은 기존 상태 때문에 리스를 잡을 수 없으면 그 이유를 코드가 붙은 오류로 반환합니다.
+// 잡을 수 있으면 nil입니다. DynamoDB 조건식과 같은 규칙입니다.
+func claimRejection(state *customTypes.OcrJobState, now time.Time, maxAttempts int) error {
+	if state == nil || state.Status == customTypes.JobStatusPending {
+		return nil
+	}
+	key := state.JobId + "/" + state.Position
+	switch state.Status {
+	case customTypes.JobStatusCompleted:
+		return utils.Errorf(customTypes.ErrorCodeJobDuplicate, "job %s already completed", key)
+	case customTypes.JobStatusProcessing:
+		if now.Before(state.LeaseExpiresAt) {
+			return utils.Errorf(customTypes.ErrorCodeJobInProgress, "job %s is being processed until %s", key, state.LeaseExpiresAt.Format(time.RFC3339))
+		}
+	case customTypes.JobStatusFailed:
+		if !state.Retryable {
+			return utils.Errorf(customTypes.ErrorCodeJobDuplicate, "job %s failed permanently [%s]", key, state.ErrorCode)
+		}
+	}
+	if state.Attempts >= maxAttempts {
+		return utils.Errorf(customTypes.ErrorCodeJobDuplicate, "job %s gave up after %d attempts", key, state.Attempts)
+	}
+	return nil
+}
+
+// DynamoJobStateStore는 OcrQueueStatus 테이블(jobId 파티션 키, position 정렬 키)에 조건부 쓰기로 상태를 기록합니다.
+type DynamoJobStateStore struct {
+	table  customTypes.TableName
+	config JobStateConfig
+}
+
+// NewDynamoJobStateStore는 테이블 이름과 설정으로 저장소를 생성합니다.
+func NewDynamoJobStateStore(table customTypes.TableName, config JobStateConfig) *DynamoJobStateStore {
+	return &DynamoJobStateStore{table: table, config: config}
+}
+
+// jobStateKey는 작업 상태 항목의 기본 키입니다.
+func jobStateKey(jobId, position string) map[string]ddbTypes.AttributeValue {
+	return map[string]ddbTypes.AttributeValue{
+		"jobId":    &ddbTypes.AttributeValueMemberS{Value: jobId},
+		"position": &ddbTypes.AttributeValueMemberS{Value: position},
+	}
+}
+
+// Claim은 항목이 없거나, PENDING이거나, 리스가 만료되었거나, 재시도 가능한 실패 상태일 때만 리스를 잡습니다.
+func (d *DynamoJobStateStore) Claim(ctx context.Context, jobId, position, imageUrl string) (*JobLease, error) {
+	now := time.Now().UTC()
+	lease := &JobLease{JobId: jobId, Position: position, Owner: utils.NewRequestId(), ExpiresAt: now.Add(d.config.leaseDuration(ctx, now))}
+
+	nowValue, err := attributevalue.Marshal(now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal claim time: %w", err)
+	}
+	client, err := dynamoDBClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out, err := client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(string(d.table)),
+		Key:       jobStateKey(jobId, position),
+		UpdateExpression: aws.String("SET #status = :processing, leaseOwner = :owner, leaseExpiresAt = :expires, " +
+			"imageUrl = :imageUrl, startedAt = :now, updatedAt = :now, createdAt = if_not_exists(createdAt, :now) " +
+			"REMOVE errorCode, errorMessage, retryable ADD attempts :one"),
+		ConditionExpression: aws.String("attribute_not_exists(jobId) OR #status = :pending OR " +
+			"(attempts < :maxAttempts AND ((#status = :processing AND leaseExpiresAt < :nowUnix) OR (#status = :failed AND retryable = :true)))"),
+		ExpressionAttributeNames: map[string]string{"#status": "status"},
+		ExpressionAttributeValues: map[string]ddbTypes.AttributeValue{
+			":processing":  &ddbTypes.AttributeValueMemberS{Value: string(customTypes.JobStatusProcessing)},
+			":pending":     &ddbTypes.AttributeValueMemberS{Value: string(customTypes.JobStatusPending)},
+			":failed":      &ddbTypes.AttributeValueMemberS{Value: string(customTypes.JobStatusFailed)},
+			":owner":       &ddbTypes.AttributeValueMemberS{Value: lease.Owner},
+			":expires":     &ddbTypes.AttributeValueMemberN{Value: strconv.FormatInt(lease.ExpiresAt.Unix(), 10)},
+			":nowUnix":     &ddbTypes.AttributeValueMemberN{Value: strconv.FormatInt(now.Unix(), 10)},
+			":now":         nowValue,
+			":imageUrl":    &ddbTypes.AttributeValueMemberS{Value: imageUrl},
+			":one":         &ddbTypes.AttributeValueMemberN{Value: "1"},
+			":maxAttempts": &ddbTypes.AttributeValueMemberN{Value: strconv.Itoa(d.config.MaxAttempts)},
+			":true":        &ddbTypes.AttributeValueMemberBOOL{Value: true},
+		},
+		ReturnValues:                        ddbTypes.ReturnValueAllNew,
+		ReturnValuesOnConditionCheckFailure: ddbTypes.ReturnValuesOnConditionCheckFailureAllOld,
+	})
+	if err != nil {
+		var conditionErr *ddbTypes.ConditionalCheckFailedException
+		if !errors.As(err, &conditionErr) {
+			return nil, utils.Errorf(customTypes.ErrorCodePersistence, "failed to claim job %s/%s: %w", jobId, position, err)
+		}
+		var existing customTypes.OcrJobState
+		if err := attributevalue.UnmarshalMap(conditionErr.Item, &existing); err != nil {
+			return nil, utils.Errorf(customTypes.ErrorCodePersistence, "failed to read job %s/%s: %w", jobId, position, err)
+		}
+		if rejection := claimRejection(&existing, now, d.config.MaxAttempts); rejection != nil {
+			return nil, rejection
+		}
+		// 조건 검사와 읽기 사이에 상태가 바뀐 경우입니다. 다음 전달에서 다시 판단합니다.
+		return nil, utils.Errorf(customTypes.ErrorCodeJobInProgress, "job %s/%s changed while claiming", jobId, position)
+	}
+
+	var claimed customTypes.OcrJobState
+	if err := attributevalue.UnmarshalMap(out.Attributes, &claimed); err != nil {
+		return nil, utils.Errorf(customTypes.ErrorCodePersistence, "failed to read claimed job %s/%s: %w", jobId, position, err)
+	}
+	lease.Attempt = claimed.Attempts
+	return lease, nil
+}
+
+// Complete는 리스를 가진 작업을 COMPLETED로 바꿉니다.
+func (d *DynamoJobStateStore) Complete(ctx context.Context, lease *JobLease, imageUrl string) error {
+	now, err := attributevalue.Marshal(time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("failed to marshal completion time: %w", err)
+	}
+	update := "SET #status = :status, completedAt = :now, updatedAt = :now"
+	values := map[string]ddbTypes.AttributeValue{
+		":status": &ddbTypes.AttributeValueMemberS{Value: string(customTypes.JobStatusCompleted)},
+		":now":    now,
+	}
+	if imageUrl != "" {
+		update += ", imageUrl = :imageUrl"
+		values[":imageUrl"] = &ddbTypes.AttributeValueMemberS{Value: imageUrl}
+	}
+	return d.release(ctx, lease, update+" REMOVE leaseOwner, leaseExpiresAt", values)
+}
+
+// Fail은 리스를 가진 작업을 FAILED로 바꿉니다.
+func (d *DynamoJobStateStore) Fail(ctx context.Context, lease *JobLease, cause error) error {
+	now, err := attributevalue.Marshal(time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("failed to marshal failure time: %w", err)
+	}
+	return d.release(ctx, lease, "SET #status = :status, errorCode = :code, errorMessage = :message, retryable = :retryable, updatedAt = :now "+
+		"REMOVE leaseOwner, leaseExpiresAt",
+		map[string]ddbTypes.AttributeValue{
+			":status":    &ddbTypes.AttributeValueMemberS{Value: string(customTypes.JobStatusFailed)},
+			":code":      &ddbTypes.AttributeValueMemberS{Value: string(utils.ErrorCodeOf(cause))},
+			":message":   &ddbTypes.AttributeValueMemberS{Value: cause.Error()},
+			":retryable": &ddbTypes.AttributeValueMemberBOOL{Value: utils.IsRetryable(cause)},
+			":now":       now,
+		})
+}
+
+// release는 리스 소유자가 같을 때만 상태를 바꿉니다. 리스가 만료되어 다른 워커가 잡았으면 오류를 반환합니다.
+func (d *DynamoJobStateStore) release(ctx context.Context, lease *JobLease, update string, values map[string]ddbTypes.AttributeValue) error {
+	client, err := dynamoDBClient(ctx)
+	if err != nil {
+		return err
+	}
+	values[":owner"] = &ddbTypes.AttributeValueMemberS{Value: lease.Owner}
+	_, err = client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:                 aws.String(string(d.table)),
+		Key:                       jobStateKey(lease.JobId, lease.Position),
+		UpdateExpression:          aws.String(update),
+		ConditionExpression:       aws.String("leaseOwner = :owner"),
+		ExpressionAttributeNames:  map[string]string{"#status": "status"},
+		ExpressionAttributeValues: values,
+	})
+	if err != nil {
+		var conditionErr *ddbTypes.ConditionalCheckFailedException
+		if errors.As(err, &conditionErr) {
+			return fmt.Errorf("lease on job %s/%s was lost", lease.JobId, lease.Position)
+		}
+		return utils.Errorf(customTypes.ErrorCodePersistence, "failed to update job %s/%s: %w", lease.JobId, lease.Position, err)
+	}
+	return nil
+}
+
+// Get은 작업/위치의 상태를 조회합니다.
+func (d *DynamoJobStateStore) Get(ctx context.Context, jobId, position string) (*customTypes.OcrJobState, error) {
+	client, err := dynamoDBClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out, err := client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName:      aws.String(string(d.table)),
+		Key:            jobStateKey(jobId, position),
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, utils.Errorf(customTypes.ErrorCodePersistence, "failed to get job %s/%s: %w", jobId, position, err)
+	}
+	if len(out.Item) == 0 {
+		return nil, nil
+	}
+	var state customTypes.OcrJobState
+	if err := attributevalue.UnmarshalMap(out.Item, &state); err != nil {
+		return nil, utils.Errorf(customTypes.ErrorCodePersistence, "failed to read job %s/%s: %w", jobId, position, err)
+	}
+	return &state, nil
+}
+
+// List는 jobId 파티션의 항목을 모두 조회합니다. 정렬 키(position) 순으로 반환됩니다.
+func (d *DynamoJobStateStore) List(ctx context.Context, jobId string) ([]customTypes.OcrJobState, error) {
+	client, err := dynamoDBClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var states []customTypes.OcrJobState
+	paginator := dynamodb.NewQueryPaginator(client, &dynamodb.QueryInput{
+		TableName:              aws.String(string(d.table)),
+		KeyConditionExpression: aws.String("jobId = :jobId"),
+		ExpressionAttributeValues: map[string]ddbTypes.AttributeValue{
+			":jobId": &ddbTypes.AttributeValueMemberS{Value: jobId},
+		},
+		ConsistentRead: aws.Bool(true),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, utils.Errorf(customTypes.ErrorCodePersistence, "failed to list job %s: %w", jobId, err)
+		}
+		var pageStates []customTypes.OcrJobState
+		if err := attributevalue.UnmarshalListOfMaps(page.Items, &pageStates); err != nil {
+			return nil, utils.Errorf(customTypes.ErrorCodePersistence, "failed to read job %s: %w", jobId, err)
+		}
+		states = append(states, pageStates...)
+	}
+	return states, nil
+}
+
+// MemoryJobStateStore는 프로세스 메모리에 상태를 저장하는 JobStateStore입니다. 로컬 서버와 테스트용입니다.
+type MemoryJobStateStore struct {
+	mu     sync.Mutex
+	states map[string]customTypes.OcrJobState
+	config JobStateConfig
+}
+
+// NewMemoryJobStateStore는 빈 메모리 저장소를 생성합니다.
+func NewMemoryJobStateStore(config JobStateConfig) *MemoryJobStateStore {
+	return &MemoryJobStateStore{states: make(map[string]customTypes.OcrJobState), config: config}
+}
+
+// Claim은 DynamoJobStateStore와 같은 규칙으로 리스를 잡습니다.
+func (m *MemoryJobStateStore) Claim(ctx context.Context, jobId, position, imageUrl string) (*JobLease, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now().UTC()
+	key := jobId + "/" + position
+	state, exists := m.states[key]
+	var existing *customTypes.OcrJobState
+	if exists {
+		existing = &state
+	}
+	if err := claimRejection(existing, now, m.config.MaxAttempts); err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		state = customTypes.OcrJobState{JobId: jobId, Position: position, CreatedAt: now}
+	}
+	lease := &JobLease{JobId: jobId, Position: position, Owner: utils.NewRequestId(), Attempt: state.Attempts + 1, ExpiresAt: now.Add(m.config.leaseDuration(ctx, now))}
+	state.Status = customTypes.JobStatusProcessing
+	state.Attempts = lease.Attempt
+	state.LeaseOwner = lease.Owner
+	state.LeaseExpiresAt = lease.ExpiresAt
+	state.ImageUrl = imageUrl
+	state.ErrorCode = ""
+	state.ErrorMessage = ""
+	state.Retryable = false
+	state.StartedAt = now
+	state.UpdatedAt = now
+	m.states[key] = state
+	return lease, nil
+}
+
+// Complete는 리스를 가진 작업을 COMPLETED로 바꿉니다.
+func (m *MemoryJobStateStore) Complete(ctx context.Context, lease *JobLease, imageUrl string) error {
+	return m.release(lease, func(state *customTypes.OcrJobState, now time.Time) {
+		state.Status = customTypes.JobStatusCompleted
+		state.CompletedAt = now
+		if imageUrl != "" {
+			state.ImageUrl = imageUrl
+		}
+	})
+}
+
+// Fail은 리스를 가진 작업을 FAILED로 바꿉니다.
+func (m *MemoryJobStateStore) Fail(ctx context.Context, lease *JobLease, cause error) error {
+	return m.release(lease, func(state *customTypes.OcrJobState, now time.Time) {
+		state.Status = customTypes.JobStatusFailed
+		state.ErrorCode = utils.ErrorCodeOf(cause)
+		state.ErrorMessage = cause.Error()
+		state.Retryable = utils.IsRetryable(cause)
+	})
+}
+
+// release는 리스 소유자가 같을 때만 update를 적용하고 리스를 해제합니다.
+func (m *MemoryJobStateStore) release(lease *JobLease, update func(state *customTypes.OcrJobState, now time.Time)) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := lease.JobId + "/" + lease.Position
+	state, ok := m.states[key]
+	if !ok || state.LeaseOwner != lease.Owner {
+		return fmt.Errorf("lease on job %s was lost", key)
+	}
+	now := time.Now().UTC()
+	update(&state, now)
+	state.LeaseOwner = ""
+	state.LeaseExpiresAt = time.Time{}
+	state.UpdatedAt = now
+	m.states[key] = state
+	return nil
+}
+
+// Get은 작업/위치의 상태를 조회합니다.
+func (m *MemoryJobStateStore) Get(ctx context.Context, jobId, position string) (*customTypes.OcrJobState, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	state, ok := m.states[jobId+"/"+position]
+	if !ok {
+		return nil, nil
+	}
+	return &state, nil
+}
+
+// List는 작업의 모든 위치 상태를 position 순으로 조회합니다.
+func (m *MemoryJobStateStore) List(ctx context.Context, jobId string) ([]customTypes.OcrJobState, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var states []customTypes.OcrJobState
+	for _, state := range m.states {
+		if state.JobId == jobId {
+			states = append(states, state)
+		}
+	}
+	sort.Slice(states, func(a, b int) bool { return states[a].Position < states[b].Position })
+	return states, nil
+}
+
+// claimJob은 워크플로우 실행 전에 작업/위치의 리스를 잡습니다.
+// 저장소가 없거나 jobId가 비어 있으면(검증 단계에서 거절됨) 리스 없이 진행합니다.
+func (s *OcrService) claimJob(ctx context.Context, queueState customTypes.OcrQueueState) (*JobLease, error) {
+	if s.jobStates == nil || queueState.JobId == "" {
+		return nil, nil
+	}
+	imageUrl := ""
+	if queueState.CrawlResult != nil && queueState.Mode != customTypes.OcrJobModeAllPositions {
+		imageUrl = queueState.CrawlResult.GetImageUrlByPosition(queueState.CurrentPosition)
+	}
+	lease, err := s.jobStates.Claim(ctx, queueState.JobId, queueState.JobPosition(), imageUrl)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("Claimed job %s/%s (attempt %d, lease until %s)", lease.JobId, lease.Position, lease.Attempt, lease.ExpiresAt.Format(time.RFC3339))
+	return lease, nil
+}
+
+// releaseJob은 워크플로우 결과에 따라 작업을 COMPLETED 또는 FAILED로 기록합니다.
+// 결과는 이미 처리되었으므로 상태 기록 실패는 경고만 남기고, 리스가 만료되면 다음 전달이 다시 처리합니다.
+func (s *OcrService) releaseJob(ctx context.Context, lease *JobLease, result *customTypes.OcrResult, workflowErr error) {
+	if lease == nil {
+		return
+	}
+	// 호출 컨텍스트가 끝나도 상태는 기록되도록 취소를 전파하지 않습니다.
+	ctx = context.WithoutCancel(ctx)
+	var err error
+	if workflowErr == nil {
+		err = s.jobStates.Complete(ctx, lease, result.ImageUrl)
+	} else {
+		err = s.jobStates.Fail(ctx, lease, workflowErr)
+	}
+	if err != nil {
+		log.Printf("WARNING: Failed to record job %s/%s state: %v", lease.JobId, lease.Position, err)
+	}
+}
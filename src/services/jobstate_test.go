@@ -0,0 +1,229 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	customTypes "github.com/ndns-dev/ndns-tesseract/src/types"
+	"github.com/ndns-dev/ndns-tesseract/src/utils"
+)
+
+// wantCode는 err의 오류 코드가 want인지 확인합니다. want가 빈 값이면 오류가 없어야 합니다.
+func wantCode(t *testing.T, err error, want customTypes.ErrorCode) {
+	t.Helper()
+	if want == "" {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return
+	}
+	if err == nil {
+		t.Fatalf("expected %s, got no error", want)
+	}
+	if got := utils.ErrorCodeOf(err); got != want {
+		t.Fatalf("error code = %s, want %s (%v)", got, want, err)
+	}
+}
+
+func TestMemoryJobStateStoreTransitions(t *testing.T) {
+	config := JobStateConfig{LeaseDuration: time.Minute, MaxAttempts: 3}
+
+	tests := []struct {
+		name string
+		// setup은 대상 작업을 원하는 상태로 만듭니다. 마지막 Claim의 결과를 확인합니다.
+		setup     func(t *testing.T, store *MemoryJobStateStore)
+		wantClaim customTypes.ErrorCode
+	}{
+		{
+			name:  "new job",
+			setup: func(t *testing.T, store *MemoryJobStateStore) {},
+		},
+		{
+			name: "lease held by another worker",
+			setup: func(t *testing.T, store *MemoryJobStateStore) {
+				mustClaim(t, store)
+			},
+			wantClaim: customTypes.ErrorCodeJobInProgress,
+		},
+		{
+			name: "completed job",
+			setup: func(t *testing.T, store *MemoryJobStateStore) {
+				lease := mustClaim(t, store)
+				wantCode(t, store.Complete(context.Background(), lease, "https://img/1"), "")
+			},
+			wantClaim: customTypes.ErrorCodeJobDuplicate,
+		},
+		{
+			name: "retryable failure",
+			setup: func(t *testing.T, store *MemoryJobStateStore) {
+				lease := mustClaim(t, store)
+				cause := utils.Errorf(customTypes.ErrorCodeImageUnavailable, "server returned 503")
+				wantCode(t, store.Fail(context.Background(), lease, cause), "")
+			},
+		},
+		{
+			name: "permanent failure",
+			setup: func(t *testing.T, store *MemoryJobStateStore) {
+				lease := mustClaim(t, store)
+				cause := utils.Errorf(customTypes.ErrorCodeImageNotFound, "server returned 404")
+				wantCode(t, store.Fail(context.Background(), lease, cause), "")
+			},
+			wantClaim: customTypes.ErrorCodeJobDuplicate,
+		},
+		{
+			name: "retryable failures up to max attempts",
+			setup: func(t *testing.T, store *MemoryJobStateStore) {
+				for i := 0; i < config.MaxAttempts; i++ {
+					lease := mustClaim(t, store)
+					wantCode(t, store.Fail(context.Background(), lease, utils.Errorf(customTypes.ErrorCodeOcrEngine, "crashed")), "")
+				}
+			},
+			wantClaim: customTypes.ErrorCodeJobDuplicate,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := NewMemoryJobStateStore(config)
+			tt.setup(t, store)
+			_, err := store.Claim(context.Background(), "job-1", "FirstImageUrl", "https://img/1")
+			wantCode(t, err, tt.wantClaim)
+		})
+	}
+}
+
+func TestMemoryJobStateStoreExpiredLease(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryJobStateStore(JobStateConfig{LeaseDuration: time.Millisecond, MaxAttempts: 5})
+
+	first := mustClaim(t, store)
+	time.Sleep(5 * time.Millisecond)
+	second := mustClaim(t, store)
+	if second.Attempt != 2 || second.Owner == first.Owner {
+		t.Fatalf("second lease = %+v, first = %+v", second, first)
+	}
+
+	// 리스를 빼앗긴 첫 번째 워커는 상태를 바꾸지 못합니다.
+	if err := store.Complete(ctx, first, ""); err == nil {
+		t.Error("expected the expired lease to be rejected")
+	}
+	wantCode(t, store.Complete(ctx, second, "https://img/final"), "")
+
+	state, err := store.Get(ctx, "job-1", "FirstImageUrl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.Status != customTypes.JobStatusCompleted || state.Attempts != 2 || state.ImageUrl != "https://img/final" {
+		t.Errorf("unexpected state %+v", state)
+	}
+	if state.LeaseOwner != "" || !state.LeaseExpiresAt.IsZero() || state.CompletedAt.IsZero() {
+		t.Errorf("lease was not released: %+v", state)
+	}
+}
+
+func TestMemoryJobStateStoreLeaseFollowsDeadline(t *testing.T) {
+	config := JobStateConfig{LeaseDuration: time.Minute, MaxAttempts: 5}
+	deadlineCtx, cancel := context.WithTimeout(context.Background(), 15*time.Minute)
+	defer cancel()
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want time.Duration
+	}{
+		{name: "no deadline uses configured duration", ctx: context.Background(), want: time.Minute},
+		{name: "deadline longer than configured duration", ctx: deadlineCtx, want: 15*time.Minute + JobLeaseMargin},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := NewMemoryJobStateStore(config)
+			before := time.Now()
+			lease, err := store.Claim(tt.ctx, "job-1", "FirstImageUrl", "https://img/1")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := lease.ExpiresAt.Sub(before); got < tt.want-time.Second || got > tt.want+time.Second {
+				t.Errorf("lease lasts %s, want about %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMemoryJobStateStoreFailureDetails(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryJobStateStore(JobStateConfig{LeaseDuration: time.Minute, MaxAttempts: 5})
+
+	lease := mustClaim(t, store)
+	wantCode(t, store.Fail(ctx, lease, utils.Errorf(customTypes.ErrorCodeImageTimeout, "took too long")), "")
+	state, _ := store.Get(ctx, "job-1", "FirstImageUrl")
+	if state.Status != customTypes.JobStatusFailed || state.ErrorCode != customTypes.ErrorCodeImageTimeout || !state.Retryable {
+		t.Fatalf("unexpected state %+v", state)
+	}
+
+	// 다시 잡으면 이전 오류는 지워집니다.
+	mustClaim(t, store)
+	state, _ = store.Get(ctx, "job-1", "FirstImageUrl")
+	if state.Status != customTypes.JobStatusProcessing || state.ErrorCode != "" || state.ErrorMessage != "" || state.Retryable {
+		t.Errorf("unexpected state %+v", state)
+	}
+}
+
+func TestMemoryJobStateStoreList(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryJobStateStore(JobStateConfig{LeaseDuration: time.Minute, MaxAttempts: 5})
+	for _, position := range []string{"LastImageUrl", "FirstImageUrl", customTypes.JobPositionAll} {
+		if _, err := store.Claim(ctx, "job-1", position, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := store.Claim(ctx, "job-2", "FirstImageUrl", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	states, err := store.List(ctx, "job-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var positions []string
+	for _, state := range states {
+		positions = append(positions, state.Position)
+	}
+	want := []string{customTypes.JobPositionAll, "FirstImageUrl", "LastImageUrl"}
+	if len(positions) != len(want) {
+		t.Fatalf("positions = %v, want %v", positions, want)
+	}
+	for i := range want {
+		if positions[i] != want[i] {
+			t.Fatalf("positions = %v, want %v", positions, want)
+		}
+	}
+
+	if state, err := store.Get(ctx, "job-3", "FirstImageUrl"); err != nil || state != nil {
+		t.Errorf("Get(missing) = %+v, %v", state, err)
+	}
+}
+
+// mustClaim은 job-1/FirstImageUrl의 리스를 잡습니다.
+func mustClaim(t *testing.T, store JobStateStore) *JobLease {
+	t.Helper()
+	lease, err := store.Claim(context.Background(), "job-1", "FirstImageUrl", "https://img/1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return lease
+}
+
+func TestHandleOcrWorkflowValidatesBeforeClaim(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryJobStateStore(JobStateConfig{LeaseDuration: time.Minute, MaxAttempts: 3})
+	service := NewOcrService(utils.NewFakeOcrEngine(""), WithResultCache(nil), WithJobStateStore(store))
+
+	// 잘못된 요청은 작업 상태를 남기지 않으므로 고친 요청을 같은 JobId로 다시 보낼 수 있습니다.
+	_, err := service.HandleOcrWorkflow(ctx, customTypes.OcrQueueState{JobId: "job-1", CurrentPosition: "Middle", CrawlResult: &customTypes.CrawlResult{}})
+	wantCode(t, err, customTypes.ErrorCodeValidation)
+	if state, err := store.Get(ctx, "job-1", "Middle"); err != nil || state != nil {
+		t.Errorf("job state = %+v (%v), want none", state, err)
+	}
+}
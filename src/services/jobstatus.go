@@ -0,0 +1,126 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	customTypes "github.com/ndns-dev/ndns-tesseract/src/types"
+	"github.com/ndns-dev/ndns-tesseract/src/utils"
+)
+
+// jobStatusPriority는 위치별 상태를 작업 상태 하나로 합칠 때의 우선순위입니다 (클수록 우선).
+var jobStatusPriority = map[customTypes.JobStatus]int{
+	customTypes.JobStatusCompleted:  1,
+	customTypes.JobStatusFailed:     2,
+	customTypes.JobStatusPending:    3,
+	customTypes.JobStatusProcessing: 4,
+}
+
+// GetJobStatus는 OcrQueueStatus와 OcrResult 테이블을 읽어 작업 상태를 반환합니다.
+// position이 비어 있으면 작업의 모든 위치를, 아니면 해당 위치만 조회합니다.
+func (s *OcrService) GetJobStatus(ctx context.Context, jobId, position string) (*customTypes.JobStatusResponse, error) {
+	if s.jobStates == nil {
+		return nil, utils.Errorf(customTypes.ErrorCodeInternal, "job state tracking is disabled")
+	}
+
+	var states []customTypes.OcrJobState
+	if position != "" {
+		state, err := s.jobStates.Get(ctx, jobId, position)
+		if err != nil {
+			return nil, err
+		}
+		if state != nil {
+			states = append(states, *state)
+		}
+	} else {
+		var err error
+		if states, err = s.jobStates.List(ctx, jobId); err != nil {
+			return nil, err
+		}
+	}
+	if len(states) == 0 {
+		if position != "" {
+			return nil, utils.Errorf(customTypes.ErrorCodeJobNotFound, "job %s has no state for position %s", jobId, position)
+		}
+		return nil, utils.Errorf(customTypes.ErrorCodeJobNotFound, "job %s not found", jobId)
+	}
+
+	response := &customTypes.JobStatusResponse{
+		JobId:     jobId,
+		Positions: make([]customTypes.JobPositionStatus, 0, len(states)),
+	}
+	for _, state := range states {
+		status := newJobPositionStatus(state)
+		if state.Status == customTypes.JobStatusCompleted && state.ImageUrl != "" {
+			// 결과 조회가 실패해도 상태는 보여줄 수 있으므로 경고만 남깁니다.
+			// OcrResult는 이미지 URL마다 하나이므로 같은 이미지를 나중에 처리한 다른 작업의 결과일 수 있어 JobId를 확인합니다.
+			result, err := s.results.GetByImageUrl(ctx, state.ImageUrl)
+			if err != nil {
+				log.Printf("WARNING: Failed to load OCR result for job %s/%s: %v", jobId, state.Position, err)
+			} else if result != nil && result.JobId != jobId {
+				log.Printf("OCR result for %s now belongs to job %s, omitting it from job %s/%s", state.ImageUrl, result.JobId, jobId, state.Position)
+			} else if result != nil {
+				status.OcrText = result.OcrText
+				status.MeanConfidence = result.MeanConfidence
+				status.Disclosure = result.Disclosure
+				status.Positions = result.Positions
+				status.CacheHit = result.CacheHit
+			}
+		}
+		if jobStatusPriority[state.Status] > jobStatusPriority[response.Status] {
+			response.Status = state.Status
+		}
+		response.Positions = append(response.Positions, status)
+	}
+	return response, nil
+}
+
+// ListJobResults는 작업에서 저장한 위치별 OCR 결과를 position 순으로 한 페이지씩 조회합니다.
+// OcrResult는 이미지 URL마다 하나이므로 같은 이미지를 나중에 처리한 다른 작업의 결과는 나오지 않습니다.
+func (s *OcrService) ListJobResults(ctx context.Context, jobId string, query ResultQuery) (*ResultPage, error) {
+	return s.results.ListByJobId(ctx, jobId, query)
+}
+
+// newJobPositionStatus는 작업 상태 항목을 응답 형식으로 바꿉니다.
+func newJobPositionStatus(state customTypes.OcrJobState) customTypes.JobPositionStatus {
+	status := customTypes.JobPositionStatus{
+		Position:  state.Position,
+		Status:    state.Status,
+		Attempts:  state.Attempts,
+		ImageUrl:  state.ImageUrl,
+		CreatedAt: state.CreatedAt,
+		UpdatedAt: state.UpdatedAt,
+		StartedAt: optionalTime(state.StartedAt),
+	}
+
+	switch state.Status {
+	case customTypes.JobStatusProcessing:
+		status.LeaseExpiresAt = optionalTime(state.LeaseExpiresAt)
+	case customTypes.JobStatusCompleted:
+		status.CompletedAt = optionalTime(state.CompletedAt)
+		status.DurationMs = durationMs(state.StartedAt, state.CompletedAt)
+	case customTypes.JobStatusFailed:
+		status.ErrorCode = state.ErrorCode
+		status.ErrorMessage = state.ErrorMessage
+		status.Retryable = state.Retryable
+		status.DurationMs = durationMs(state.StartedAt, state.UpdatedAt)
+	}
+	return status
+}
+
+// optionalTime은 값이 없는 시각을 nil로 바꿉니다.
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+// durationMs는 두 시각 사이의 밀리초를 반환합니다. 어느 한쪽이 없으면 0입니다.
+func durationMs(start, end time.Time) int64 {
+	if start.IsZero() || end.IsZero() || end.Before(start) {
+		return 0
+	}
+	return end.Sub(start).Milliseconds()
+}
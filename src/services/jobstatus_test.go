@@ -0,0 +1,73 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	customTypes "github.com/ndns-dev/ndns-tesseract/src/types"
+	"github.com/ndns-dev/ndns-tesseract/src/utils"
+)
+
+// newStatusTestService는 메모리 저장소를 사용하는 OcrService를 생성합니다.
+func newStatusTestService() (*OcrService, *MemoryJobStateStore, *MemoryResultStore) {
+	jobStates := NewMemoryJobStateStore(JobStateConfig{LeaseDuration: time.Minute, MaxAttempts: 3})
+	results := NewMemoryResultStore()
+	service := NewOcrService(utils.NewFakeOcrEngine(""),
+		WithJobStateStore(jobStates),
+		WithResultStore(results),
+		WithResultCache(nil),
+	)
+	return service, jobStates, results
+}
+
+func TestGetJobStatus(t *testing.T) {
+	ctx := context.Background()
+	service, jobStates, results := newStatusTestService()
+
+	complete := func(jobId, position, imageUrl string) {
+		lease, err := jobStates.Claim(ctx, jobId, position, imageUrl)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := jobStates.Complete(ctx, lease, imageUrl); err != nil {
+			t.Fatal(err)
+		}
+	}
+	complete("job-1", "FirstImageUrl", "https://img/shared")
+	if _, err := jobStates.Claim(ctx, "job-1", "LastImageUrl", "https://img/last"); err != nil {
+		t.Fatal(err)
+	}
+	if err := results.Save(ctx, customTypes.OcrResult{ImageUrl: "https://img/shared", JobId: "job-1", OcrText: "job-1 text"}); err != nil {
+		t.Fatal(err)
+	}
+
+	response, err := service.GetJobStatus(ctx, "job-1", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.Status != customTypes.JobStatusProcessing || len(response.Positions) != 2 {
+		t.Fatalf("unexpected response %+v", response)
+	}
+	if first := response.Positions[0]; first.Status != customTypes.JobStatusCompleted || first.OcrText != "job-1 text" {
+		t.Errorf("first position = %+v", first)
+	}
+
+	// 다른 작업이 같은 이미지를 다시 처리하면 job-1의 상태에는 그 결과가 보이지 않아야 합니다.
+	complete("job-2", "FirstImageUrl", "https://img/shared")
+	if err := results.Save(ctx, customTypes.OcrResult{ImageUrl: "https://img/shared", JobId: "job-2", OcrText: "job-2 text"}); err != nil {
+		t.Fatal(err)
+	}
+	response, err = service.GetJobStatus(ctx, "job-1", "FirstImageUrl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first := response.Positions[0]; first.Status != customTypes.JobStatusCompleted || first.OcrText != "" {
+		t.Errorf("job-1 shows another job's result: %+v", first)
+	}
+
+	_, err = service.GetJobStatus(ctx, "missing", "")
+	wantCode(t, err, customTypes.ErrorCodeJobNotFound)
+	_, err = service.GetJobStatus(ctx, "job-1", "SecondStickerUrl")
+	wantCode(t, err, customTypes.ErrorCodeJobNotFound)
+}
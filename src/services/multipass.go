@@ -0,0 +1,86 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"log"
+	"os"
+	"strconv"
+	"sync"
+
+	customTypes "github.com/ndns-dev/ndns-tesseract/src/types"
+)
+
+// DefaultVariantConcurrency는 다중 패스 OCR에서 동시에 실행하는 변형 수의 기본값입니다.
+const DefaultVariantConcurrency = 3
+
+// ocrVariantsFromEnv는 OCR_MULTI_PASS=true면 기본 변형 목록을, 아니면 nil을 반환합니다.
+func ocrVariantsFromEnv() []customTypes.OcrVariant {
+	if enabled, _ := strconv.ParseBool(os.Getenv("OCR_MULTI_PASS")); enabled {
+		return customTypes.DefaultOcrVariants()
+	}
+	return nil
+}
+
+// variantConcurrencyFromEnv는 OCR_VARIANT_CONCURRENCY로 설정된 동시 실행 수를 반환합니다.
+func variantConcurrencyFromEnv() int {
+	if raw := os.Getenv("OCR_VARIANT_CONCURRENCY"); raw != "" {
+		if v, err := strconv.Atoi(raw); err == nil && v > 0 {
+			return v
+		}
+		log.Printf("WARNING: Invalid OCR_VARIANT_CONCURRENCY %q, using %d", raw, DefaultVariantConcurrency)
+	}
+	return DefaultVariantConcurrency
+}
+
+// recognizeVariants는 여러 인식 설정을 병렬로 실행해 점수가 가장 높은 결과를 반환합니다.
+// 점수가 같으면 앞쪽 변형을 선택합니다. 일부 변형이 실패해도 하나라도 성공하면 그 결과를 사용합니다.
+func (s *OcrService) recognizeVariants(ctx context.Context, img image.Image, variants []recognitionVariant) (*customTypes.OcrEngineResult, error) {
+	results := make([]*customTypes.OcrEngineResult, len(variants))
+	errs := make([]error, len(variants))
+	runParallel(len(variants), s.variantConcurrency, func(i int) {
+		results[i], errs[i] = s.recognizeImage(ctx, img, variants[i].Preprocess, variants[i].Ocr)
+	})
+
+	var best *customTypes.OcrEngineResult
+	var firstErr error
+	for i, variant := range variants {
+		if errs[i] != nil {
+			log.Printf("WARNING: OCR variant %s failed: %v", variant.Name, errs[i])
+			if firstErr == nil {
+				firstErr = errs[i]
+			}
+			continue
+		}
+		result := results[i]
+		result.Variant = variant.Name
+		result.Score = s.scorer.Score(result)
+		log.Printf("OCR variant %s: score=%.3f confidence=%.1f text=%q", variant.Name, result.Score, result.MeanConfidence, result.Text)
+		if best == nil || result.Score > best.Score {
+			best = result
+		}
+	}
+
+	if best == nil {
+		return nil, fmt.Errorf("all %d OCR variants failed: %w", len(variants), firstErr)
+	}
+	log.Printf("Selected OCR variant %s (score=%.3f)", best.Variant, best.Score)
+	return best, nil
+}
+
+// runParallel은 fn(0)부터 fn(n-1)까지를 최대 limit개씩 동시에 실행하고 모두 끝날 때까지 기다립니다.
+func runParallel(n, limit int, fn func(i int)) {
+	slots := make(chan struct{}, max(limit, 1))
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+			fn(i)
+		}(i)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,168 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"sync"
+	"testing"
+	"time"
+
+	customTypes "github.com/ndns-dev/ndns-tesseract/src/types"
+	"github.com/ndns-dev/ndns-tesseract/src/utils"
+)
+
+// variantOutcome은 variantEngine이 한 PSM에 대해 돌려줄 결과입니다.
+type variantOutcome struct {
+	text       string
+	confidence float64
+	err        error
+	delay      time.Duration // 완료 순서를 바꾸기 위한 지연
+}
+
+// variantEngine은 OCR 옵션의 PSM으로 결과를 정하는 테스트 엔진입니다.
+type variantEngine struct {
+	outcomes map[int]variantOutcome
+
+	mu          sync.Mutex
+	inFlight    int
+	maxInFlight int
+}
+
+func (e *variantEngine) Name() string {
+	return "variant"
+}
+
+func (e *variantEngine) Version(ctx context.Context) string {
+	return "variant 1"
+}
+
+func (e *variantEngine) Recognize(ctx context.Context, imageBytes []byte, options customTypes.OcrOptions) (*customTypes.OcrEngineResult, error) {
+	e.mu.Lock()
+	e.inFlight++
+	e.maxInFlight = max(e.maxInFlight, e.inFlight)
+	e.mu.Unlock()
+	defer func() {
+		e.mu.Lock()
+		e.inFlight--
+		e.mu.Unlock()
+	}()
+
+	outcome := e.outcomes[options.Psm]
+	time.Sleep(outcome.delay)
+	if outcome.err != nil {
+		return nil, outcome.err
+	}
+	lines := utils.BuildLinesFromText(outcome.text, outcome.confidence)
+	return &customTypes.OcrEngineResult{
+		Engine:         e.Name(),
+		Text:           utils.JoinLineTexts(lines),
+		Lines:          lines,
+		MeanConfidence: utils.MeanWordConfidence(lines),
+	}, nil
+}
+
+// psmVariants는 PSM만 다른 변형을 주어진 순서대로 만듭니다.
+func psmVariants(psms ...int) []recognitionVariant {
+	variants := make([]recognitionVariant, 0, len(psms))
+	for _, psm := range psms {
+		options := customTypes.DefaultOcrOptions()
+		options.Psm = psm
+		variants = append(variants, recognitionVariant{Name: psmVariantName(psm), Ocr: options})
+	}
+	return variants
+}
+
+func psmVariantName(psm int) string {
+	return fmt.Sprintf("psm%d", psm)
+}
+
+func TestRecognizeVariants(t *testing.T) {
+	errEngine := errors.New("tesseract crashed")
+
+	tests := []struct {
+		name        string
+		outcomes    map[int]variantOutcome
+		psms        []int
+		wantVariant string
+		wantErr     error
+	}{
+		{
+			name: "highest score wins regardless of completion order",
+			outcomes: map[int]variantOutcome{
+				3:  {text: "뷁뛟 쉛퉯", confidence: 80},
+				6:  {text: "소정의 원고료를 받아 작성", confidence: 85, delay: 30 * time.Millisecond},
+				11: {text: "소정의 원고료를 받아 작성", confidence: 60},
+			},
+			psms:        []int{3, 6, 11},
+			wantVariant: psmVariantName(6),
+		},
+		{
+			name: "ties keep the earlier variant",
+			outcomes: map[int]variantOutcome{
+				4: {text: "카페 후기", confidence: 70, delay: 30 * time.Millisecond},
+				6: {text: "카페 후기", confidence: 70},
+			},
+			psms:        []int{4, 6},
+			wantVariant: psmVariantName(4),
+		},
+		{
+			name: "failed variants are skipped",
+			outcomes: map[int]variantOutcome{
+				3:  {err: errEngine},
+				6:  {text: "뷁뛟", confidence: 50},
+				11: {text: "맛집 추천", confidence: 75},
+			},
+			psms:        []int{3, 6, 11},
+			wantVariant: psmVariantName(11),
+		},
+		{
+			name: "all variants fail",
+			outcomes: map[int]variantOutcome{
+				3: {err: errEngine},
+				6: {err: errors.New("timeout")},
+			},
+			psms:    []int{3, 6},
+			wantErr: errEngine,
+		},
+	}
+
+	img := image.NewGray(image.Rect(0, 0, 20, 20))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewOcrService(&variantEngine{outcomes: tt.outcomes}, WithResultCache(nil))
+			best, err := service.recognizeVariants(context.Background(), img, psmVariants(tt.psms...))
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("recognizeVariants() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if best.Variant != tt.wantVariant {
+				t.Errorf("selected %s (score %.3f), want %s", best.Variant, best.Score, tt.wantVariant)
+			}
+		})
+	}
+}
+
+func TestRecognizeVariantsConcurrency(t *testing.T) {
+	outcomes := make(map[int]variantOutcome)
+	psms := []int{1, 3, 4, 5, 6, 7}
+	for _, psm := range psms {
+		outcomes[psm] = variantOutcome{text: "카페 후기", confidence: 70, delay: 10 * time.Millisecond}
+	}
+	engine := &variantEngine{outcomes: outcomes}
+	service := NewOcrService(engine, WithResultCache(nil))
+	service.variantConcurrency = 2
+
+	if _, err := service.recognizeVariants(context.Background(), image.NewGray(image.Rect(0, 0, 20, 20)), psmVariants(psms...)); err != nil {
+		t.Fatal(err)
+	}
+	if engine.maxInFlight != 2 {
+		t.Errorf("max concurrent variants = %d, want 2", engine.maxInFlight)
+	}
+}
@@ -4,29 +4,225 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"image"
 	"io"
 	"log"
 	"net/http"
 	"os"
 	"time"
 
-	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
-	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	customTypes "github.com/ndns-dev/ndns-tesseract/src/types"
 	"github.com/ndns-dev/ndns-tesseract/src/utils"
 )
 
+// OcrService는 OCR 처리 워크플로우를 수행합니다.
+// OCR 엔진은 생성 시 주입되므로 테스트에서는 utils.FakeOcrEngine으로 교체할 수 있습니다.
+type OcrService struct {
+	engine       utils.OcrEngine
+	downloader   *utils.ImageDownloader
+	detector     *DisclosureDetector
+	cache        *OcrResultCache // nil이면 캐시를 사용하지 않음
+	cacheSet     bool
+	results      ResultStore
+	resultTTL    time.Duration // 저장한 결과의 expiresAt까지의 기간 (0이면 만료하지 않음)
+	resultTTLSet bool
+
+	variants           []customTypes.OcrVariant // 요청에 변형이 없을 때 사용할 다중 패스 변형 (nil이면 단일 패스)
+	variantsSet        bool
+	variantConcurrency int
+	tileConcurrency    int
+	regionDetector     *utils.TextRegionDetector
+	scorer             *OcrResultScorer
+
+	jobStates    JobStateStore // nil이면 중복 전달을 걸러내지 않음
+	jobStatesSet bool
+
+	analyzeClient *http.Client // 분석 API 호출에 사용하는 클라이언트 (제한 시간 포함)
+}
+
+// OcrServiceOption은 OcrService의 선택적 의존성을 설정합니다.
+type OcrServiceOption func(*OcrService)
+
+// WithDisclosureDetector는 협찬 문구 탐지기를 교체합니다.
+func WithDisclosureDetector(detector *DisclosureDetector) OcrServiceOption {
+	return func(s *OcrService) {
+		s.detector = detector
+	}
+}
+
+// WithImageDownloader는 이미지 다운로더를 교체합니다.
+func WithImageDownloader(downloader *utils.ImageDownloader) OcrServiceOption {
+	return func(s *OcrService) {
+		s.downloader = downloader
+	}
+}
+
+// WithResultStore는 OCR 결과 저장소를 교체합니다.
+func WithResultStore(store ResultStore) OcrServiceOption {
+	return func(s *OcrService) {
+		s.results = store
+	}
+}
+
+// WithResultTTL은 저장하는 결과의 만료 기간을 설정합니다. 0을 전달하면 expiresAt을 기록하지 않습니다.
+func WithResultTTL(ttl time.Duration) OcrServiceOption {
+	return func(s *OcrService) {
+		s.resultTTL = ttl
+		s.resultTTLSet = true
+	}
+}
+
+// WithResultCache는 OCR 결과 캐시를 교체합니다. nil을 전달하면 캐시를 사용하지 않습니다.
+func WithResultCache(cache *OcrResultCache) OcrServiceOption {
+	return func(s *OcrService) {
+		s.cache = cache
+		s.cacheSet = true
+	}
+}
+
+// WithOcrVariants는 기본 다중 패스 변형을 설정합니다. nil을 전달하면 요청에 변형이 없을 때 단일 패스로 처리합니다.
+func WithOcrVariants(variants []customTypes.OcrVariant) OcrServiceOption {
+	return func(s *OcrService) {
+		s.variants = variants
+		s.variantsSet = true
+	}
+}
+
+// WithTextRegionDetector는 영역 모드에서 사용할 텍스트 영역 탐지기를 교체합니다.
+func WithTextRegionDetector(detector *utils.TextRegionDetector) OcrServiceOption {
+	return func(s *OcrService) {
+		s.regionDetector = detector
+	}
+}
+
+// WithResultScorer는 다중 패스 결과 점수 계산기를 교체합니다.
+func WithResultScorer(scorer *OcrResultScorer) OcrServiceOption {
+	return func(s *OcrService) {
+		s.scorer = scorer
+	}
+}
+
+// WithJobStateStore는 작업 상태 저장소를 교체합니다. nil을 전달하면 중복 전달을 걸러내지 않습니다.
+func WithJobStateStore(store JobStateStore) OcrServiceOption {
+	return func(s *OcrService) {
+		s.jobStates = store
+		s.jobStatesSet = true
+	}
+}
+
+// WithAnalyzeClient는 분석 API 호출에 사용할 HTTP 클라이언트를 교체합니다.
+func WithAnalyzeClient(client *http.Client) OcrServiceOption {
+	return func(s *OcrService) {
+		s.analyzeClient = client
+	}
+}
+
+// analyzeClientFromEnv는 ANALYZE_API_TIMEOUT(Go duration)을 제한 시간으로 하는 분석 API 클라이언트를 생성합니다.
+func analyzeClientFromEnv() *http.Client {
+	timeout := customTypes.ANALYZE_API_TIMEOUT
+	if raw := os.Getenv("ANALYZE_API_TIMEOUT"); raw != "" {
+		if v, err := time.ParseDuration(raw); err == nil && v > 0 {
+			timeout = v
+		} else {
+			log.Printf("WARNING: Invalid ANALYZE_API_TIMEOUT %q, using %s", raw, timeout)
+		}
+	}
+	return &http.Client{Timeout: timeout}
+}
+
+// NewOcrService는 주어진 OCR 엔진을 사용하는 OcrService를 생성합니다.
+// 다운로더, 탐지기, 결과 저장소, 캐시를 지정하지 않으면 환경 변수 설정으로 생성한 기본값을 사용합니다.
+func NewOcrService(engine utils.OcrEngine, opts ...OcrServiceOption) *OcrService {
+	s := &OcrService{engine: engine}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.downloader == nil {
+		s.downloader = utils.NewImageDownloaderFromEnv()
+	}
+	if s.detector == nil {
+		s.detector = NewDisclosureDetectorFromEnv()
+	}
+	if s.results == nil {
+		s.results = NewResultStoreFromEnv()
+	}
+	if !s.resultTTLSet {
+		s.resultTTL = resultTTLFromEnv()
+	}
+	if !s.cacheSet {
+		s.cache = NewOcrResultCacheFromEnv(s.results)
+	}
+	if !s.variantsSet {
+		s.variants = ocrVariantsFromEnv()
+	}
+	if s.variantConcurrency <= 0 {
+		s.variantConcurrency = variantConcurrencyFromEnv()
+	}
+	if s.tileConcurrency <= 0 {
+		s.tileConcurrency = tileConcurrencyFromEnv()
+	}
+	if s.regionDetector == nil {
+		s.regionDetector = utils.NewTextRegionDetector()
+	}
+	if s.scorer == nil {
+		s.scorer = NewOcrResultScorerFromEnv()
+	}
+	if !s.jobStatesSet {
+		s.jobStates = NewJobStateStoreFromEnv()
+	}
+	if s.analyzeClient == nil {
+		s.analyzeClient = analyzeClientFromEnv()
+	}
+	return s
+}
+
 // HandleOcrWorkflow는 OCR 워크플로우 전체를 처리합니다.
-func HandleOcrWorkflow(ctx context.Context, queueState customTypes.OcrQueueState) (*customTypes.OcrResult, error) {
-	// 1. OCR 처리
-	result, err := ProcessOcrRequest(ctx, queueState)
+// 같은 JobId+position이 이미 완료되었거나 다른 워커가 처리 중이면 OCR을 실행하지 않고
+// JOB_ALREADY_PROCESSED 또는 JOB_IN_PROGRESS 오류를 반환합니다.
+// 잘못된 요청은 작업 상태를 남기지 않도록 리스를 잡기 전에 거부합니다.
+func (s *OcrService) HandleOcrWorkflow(ctx context.Context, queueState customTypes.OcrQueueState) (*customTypes.OcrResult, error) {
+	if err := utils.NewError(customTypes.ErrorCodeValidation, queueState.Validate()); err != nil {
+		return nil, err
+	}
+	lease, err := s.claimJob(ctx, queueState)
 	if err != nil {
 		return nil, err
 	}
+	result, err := s.runOcrWorkflow(ctx, queueState)
+	s.releaseJob(ctx, lease, result, err)
+	return result, err
+}
 
-	// 2. 분석 API 호출
+// runOcrWorkflow는 OCR, 결과 저장, 분석 API 호출을 차례로 수행합니다.
+// 분석 API는 외부에 결과를 알리는 마지막 단계이므로 저장이 끝난 뒤에만 호출합니다.
+// 저장에 실패한 작업을 재시도해도 분석 API가 같은 결과를 두 번 받지 않습니다.
+func (s *OcrService) runOcrWorkflow(ctx context.Context, queueState customTypes.OcrQueueState) (*customTypes.OcrResult, error) {
+	// 1. OCR 처리 (단일 위치 또는 전체 위치)
+	// result는 호출자와 분석 API에 전달하고, results는 이미지 URL마다 저장합니다.
+	var result *customTypes.OcrResult
+	var results []customTypes.OcrResult
+	if queueState.Mode == customTypes.OcrJobModeAllPositions {
+		job, err := s.ProcessOcrJob(ctx, queueState)
+		if err != nil {
+			return nil, err
+		}
+		result, results = &job.Summary, job.Results
+	} else {
+		single, err := s.ProcessOcrRequest(ctx, queueState)
+		if err != nil {
+			return nil, err
+		}
+		result, results = single, []customTypes.OcrResult{*single}
+	}
+
+	// 2. 결과 저장
+	if err := s.saveResults(ctx, results); err != nil {
+		return nil, err
+	}
+
+	// 3. 분석 API 호출
 	analyzePayload := customTypes.AnalyzeCycleParam{
 		Result: *result,
 		State:  queueState,
@@ -42,9 +238,15 @@ func HandleOcrWorkflow(ctx context.Context, queueState customTypes.OcrQueueState
 	apiUrl := os.Getenv("API_URL") + "/api/v1/search/analyze/cycle"
 	log.Printf("Calling analyze API at URL: %s", apiUrl)
 
-	resp, err := http.Post(apiUrl, "application/json", bytes.NewBuffer(jsonPayload))
+	// 호출 컨텍스트가 취소되거나 클라이언트 제한 시간이 지나면 요청을 중단하고 재시도 가능한 오류로 반환합니다.
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiUrl, bytes.NewReader(jsonPayload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create analyze API request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.analyzeClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to call analyze API: %w", err)
+		return nil, utils.Errorf(customTypes.ErrorCodeAnalyzeApi, "failed to call analyze API: %w", err)
 	}
 	defer resp.Body.Close()
 
@@ -57,66 +259,47 @@ func HandleOcrWorkflow(ctx context.Context, queueState customTypes.OcrQueueState
 			log.Printf("analyze API error response: %s", string(body))
 		}
 		log.Printf("analyze API returned non-200 status: %v, status code: %d", apiUrl, resp.StatusCode)
-		return nil, fmt.Errorf("analyze API returned non-200 status: %d", resp.StatusCode)
+		if resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
+			return nil, utils.Errorf(customTypes.ErrorCodeAnalyzeApiRejected, "analyze API returned non-200 status: %d", resp.StatusCode)
+		}
+		return nil, utils.Errorf(customTypes.ErrorCodeAnalyzeApi, "analyze API returned non-200 status: %d", resp.StatusCode)
 	}
 
 	// 응답 내용 로깅 (성공 시에도)
 	respBody, _ := io.ReadAll(resp.Body)
 	log.Printf("analyze API response: %s", string(respBody))
 
-	// 3. DynamoDB에 저장
-	dynamoClient := utils.GetDynamoDBClient(ctx)
+	return result, nil
+}
 
-	item, err := attributevalue.MarshalMap(result)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal DynamoDB item: %w", err)
+// saveResults는 위치별 결과를 만료 시각과 함께 저장합니다.
+// 더 높은 파이프라인 버전의 결과가 이미 있는 이미지는 덮어쓰지 않고 건너뜁니다.
+func (s *OcrService) saveResults(ctx context.Context, results []customTypes.OcrResult) error {
+	var expiresAt *time.Time
+	if s.resultTTL > 0 {
+		expires := time.Now().Add(s.resultTTL)
+		expiresAt = &expires
 	}
-
-	_, err = dynamoClient.PutItem(ctx, &dynamodb.PutItemInput{
-		TableName: aws.String(string(customTypes.OcrResultTableName)),
-		Item:      item,
-	})
-	if err != nil {
-		return nil, fmt.Errorf("failed to save to DynamoDB: %w", err)
+	for _, result := range results {
+		result.ExpiresAt = expiresAt
+		if err := s.results.Save(ctx, result); err != nil {
+			if !errors.Is(err, ErrStaleResult) {
+				return err
+			}
+			log.Printf("Skipped saving OCR result: %v", err)
+		}
 	}
-
-	return result, nil
+	return nil
 }
 
 // ProcessOcrRequest는 OCR 요청을 처리합니다.
-func ProcessOcrRequest(ctx context.Context, queueState customTypes.OcrQueueState) (*customTypes.OcrResult, error) {
+func (s *OcrService) ProcessOcrRequest(ctx context.Context, queueState customTypes.OcrQueueState) (*customTypes.OcrResult, error) {
 	// queueState 전체를 로깅
 	jsonState, _ := json.MarshalIndent(queueState, "", "  ")
 	log.Printf("Processing OCR request with queueState: %s", string(jsonState))
 
-	// 필수 필드 검증
-	if queueState.JobId == "" {
-		return nil, fmt.Errorf("jobId is required")
-	}
-	if queueState.CrawlResult == nil {
-		return nil, fmt.Errorf("crawlResult is required")
-	}
-	if queueState.CurrentPosition == "" {
-		return nil, fmt.Errorf("currentPosition is required")
-	}
-
-	// CurrentPosition 유효성 검사
-	validPositions := []customTypes.OcrPosition{
-		customTypes.OcrPositionFirstImage,
-		customTypes.OcrPositionFirstSticker,
-		customTypes.OcrPositionSecondSticker,
-		customTypes.OcrPositionLastImage,
-		customTypes.OcrPositionLastSticker,
-	}
-	isValidPosition := false
-	for _, pos := range validPositions {
-		if queueState.CurrentPosition == pos {
-			isValidPosition = true
-			break
-		}
-	}
-	if !isValidPosition {
-		return nil, fmt.Errorf("invalid currentPosition: %s", queueState.CurrentPosition)
+	if err := validateQueueState(queueState, customTypes.OcrJobModeSingle); err != nil {
+		return nil, err
 	}
 
 	// 이미지 URL 가져오기
@@ -125,24 +308,203 @@ func ProcessOcrRequest(ctx context.Context, queueState customTypes.OcrQueueState
 	imageUrl := queueState.CrawlResult.GetImageUrlByPosition(queueState.CurrentPosition)
 	if imageUrl == "" {
 		log.Printf("Failed to get image URL. CrawlResult: %+v, Position: %s", queueState.CrawlResult, queueState.CurrentPosition)
-		return nil, fmt.Errorf("no image URL found for position: %s", queueState.CurrentPosition)
+		return nil, utils.Errorf(customTypes.ErrorCodeValidation, "no image URL found for position: %s", queueState.CurrentPosition)
 	}
 	log.Printf("Successfully got image URL: %s", imageUrl)
 
-	OcrResult, err := utils.PerformOCR(imageUrl)
+	return s.recognizePosition(ctx, queueState, queueState.CurrentPosition, imageUrl)
+}
+
+// recognizePosition은 한 위치의 이미지를 인식하고 협찬 문구 탐지 결과가 포함된 OcrResult를 생성합니다.
+// 같은 내용의 이미지가 캐시에 있으면 OCR 엔진을 실행하지 않고 캐시된 결과를 사용합니다.
+func (s *OcrService) recognizePosition(ctx context.Context, queueState customTypes.OcrQueueState, position customTypes.OcrPosition, imageUrl string) (*customTypes.OcrResult, error) {
+	downloaded, err := s.fetchImage(ctx, imageUrl)
 	if err != nil {
 		return nil, err
 	}
+	contentHash := utils.HashBytes(downloaded.Bytes)
+	plan := newRecognitionPlan(queueState, position, s.variants)
+	engineVersion := s.engine.Version(ctx)
+
+	var result *customTypes.OcrResult
+	if cached, ok := s.lookupCache(ctx, imageUrl, contentHash, plan.Key(), engineVersion); ok {
+		result = cached
+		result.ImageUrl = imageUrl
+		result.JobId = queueState.JobId
+		result.Position = position
+		result.Positions = nil
+		result.CacheHit = true
+	} else {
+		engineResult, err := s.recognizeDownloaded(ctx, downloaded, plan)
+		if err != nil {
+			return nil, err
+		}
 
-	// OCR 결과 생성
-	result := &customTypes.OcrResult{
-		ImageUrl:    imageUrl,
-		JobId:       queueState.JobId,
-		OcrText:     OcrResult,
-		Position:    queueState.CurrentPosition,
-		ProcessedAt: time.Now(),
-		Error:       "",
+		// OCR 결과 생성
+		result = &customTypes.OcrResult{
+			ImageUrl:        imageUrl,
+			JobId:           queueState.JobId,
+			OcrText:         engineResult.Text,
+			Lines:           engineResult.Lines,
+			MeanConfidence:  engineResult.MeanConfidence,
+			Position:        position,
+			ContentHash:     contentHash,
+			ProcessingKey:   plan.Key(),
+			Variant:         engineResult.Variant,
+			VariantScore:    engineResult.Score,
+			EngineVersion:   engineVersion,
+			PipelineVersion: customTypes.OcrPipelineVersion,
+			ProcessedAt:     time.Now(),
+			Error:           "",
+		}
+		if s.cache != nil {
+			s.cache.Put(*result)
+		}
 	}
 
+	// 협찬 문구 탐지 (사전이 바뀔 수 있으므로 캐시 적중 시에도 다시 수행)
+	result.Disclosure = s.detector.Detect(result.OcrText)
+	log.Printf("Disclosure verdict for %s: detected=%t phrase=%q score=%.2f cacheHit=%t",
+		imageUrl, result.Disclosure.Detected, result.Disclosure.MatchedPhrase, result.Disclosure.Score, result.CacheHit)
+
 	return result, nil
 }
+
+// lookupCache는 캐시가 설정된 경우 결과를 조회합니다.
+func (s *OcrService) lookupCache(ctx context.Context, imageUrl, contentHash, processingKey, engineVersion string) (*customTypes.OcrResult, bool) {
+	if s.cache == nil {
+		return nil, false
+	}
+	return s.cache.Get(ctx, imageUrl, contentHash, processingKey, engineVersion)
+}
+
+// fetchImage는 이미지를 다운로드합니다.
+func (s *OcrService) fetchImage(ctx context.Context, imageUrl string) (*utils.DownloadedImage, error) {
+	log.Printf("Fetching image bytes from URL: %s", imageUrl)
+	downloaded, err := s.downloader.Download(ctx, imageUrl)
+	if err != nil {
+		log.Printf("ERROR: Failed to fetch image bytes from URL %s: %v", imageUrl, err)
+		return nil, fmt.Errorf("failed to fetch image: %w", err)
+	}
+	log.Printf("Image fetched. Size: %d bytes, type: %s", len(downloaded.Bytes), downloaded.ContentType)
+	return downloaded, nil
+}
+
+// recognizeDownloaded는 이미지를 한 번 디코딩해 인식합니다.
+// 애니메이션 GIF/WebP면 대표 프레임을 각각 인식해 병합합니다.
+// 디코딩할 수 없는 이미지는 전처리 없이 원본 바이트를 OCR 엔진에 전달합니다.
+func (s *OcrService) recognizeDownloaded(ctx context.Context, downloaded *utils.DownloadedImage, plan recognitionPlan) (*customTypes.OcrEngineResult, error) {
+	var frames []image.Image
+	var err error
+	if utils.IsAnimatedImageType(downloaded.ContentType) {
+		frames, err = utils.DecodeAnimationFrames(downloaded.Bytes, downloaded.ContentType)
+	} else {
+		var img image.Image
+		img, _, err = utils.DecodeImage(downloaded.Bytes)
+		frames = []image.Image{img}
+	}
+	if err != nil || len(frames) == 0 {
+		log.Printf("WARNING: Failed to decode %s, sending original bytes to OCR engine: %v", downloaded.Url, err)
+		return s.engine.Recognize(ctx, downloaded.Bytes, plan.Ocr)
+	}
+
+	selected := utils.SelectRepresentativeFrames(frames, utils.DefaultMaxAnimationFrames)
+	if len(frames) > 1 {
+		log.Printf("Animated image %s: %d frames, %d selected for OCR", downloaded.Url, len(frames), len(selected))
+	}
+	if len(selected) == 1 {
+		return s.recognizeFrame(ctx, selected[0], plan)
+	}
+
+	results := make([]*customTypes.OcrEngineResult, 0, len(selected))
+	for i, frame := range selected {
+		result, err := s.recognizeFrame(ctx, frame, plan)
+		if err != nil {
+			return nil, fmt.Errorf("failed to recognize frame %d: %w", i, err)
+		}
+		results = append(results, result)
+	}
+	return mergeFrameResults(results), nil
+}
+
+// mixedVariantName은 애니메이션 프레임마다 다른 변형이 선택되었을 때 기록하는 이름입니다.
+const mixedVariantName = "mixed"
+
+// mergeFrameResults는 프레임별 인식 결과를 하나로 합칩니다. 이미 나온 줄과 같은 텍스트의 줄은 제외합니다.
+func mergeFrameResults(results []*customTypes.OcrEngineResult) *customTypes.OcrEngineResult {
+	merged := &customTypes.OcrEngineResult{}
+	seen := make(map[string]bool)
+	for _, result := range results {
+		if merged.Engine == "" {
+			merged.Engine = result.Engine
+			merged.Variant = result.Variant
+		} else if merged.Variant != result.Variant {
+			merged.Variant = mixedVariantName
+		}
+		merged.Score = max(merged.Score, result.Score)
+		merged.Duration += result.Duration
+		for _, line := range result.Lines {
+			key := string(utils.NewJamoSequence(line.Text).Runes)
+			if key == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+			merged.Lines = append(merged.Lines, line)
+		}
+	}
+	merged.Text = utils.JoinLineTexts(merged.Lines)
+	merged.MeanConfidence = utils.MeanWordConfidence(merged.Lines)
+	return merged
+}
+
+// recognizeFrame은 디코딩된 이미지 하나를 계획에 따라 인식합니다.
+// 다중 패스 변형이 있으면 변형별 결과 가운데 가장 좋은 것을 사용합니다.
+func (s *OcrService) recognizeFrame(ctx context.Context, img image.Image, plan recognitionPlan) (*customTypes.OcrEngineResult, error) {
+	if len(plan.Variants) > 0 {
+		return s.recognizeVariants(ctx, img, plan.Variants)
+	}
+	return s.recognizeImage(ctx, img, plan.Preprocess, plan.Ocr)
+}
+
+// recognizeImage는 이미지에 전처리 파이프라인을 적용하고 OCR 엔진으로 인식합니다.
+// 영역 모드에서는 텍스트 영역만 인식하고, 타일/영역 모드에서 MAX_IMAGE_DIMENSION을 넘는 이미지는 타일로 나눠 인식합니다.
+func (s *OcrService) recognizeImage(ctx context.Context, img image.Image, preprocess customTypes.PreprocessOptions, options customTypes.OcrOptions) (*customTypes.OcrEngineResult, error) {
+	pipeline := utils.NewOcrImagePipeline(preprocess)
+	if preprocess.Crop == customTypes.CropRegions {
+		if regions := s.regionDetector.Detect(img); len(regions) > 0 {
+			log.Printf("Detected %d text regions: %v", len(regions), regions)
+			return s.recognizeTiles(ctx, utils.RegionTiles(img, regions), pipeline, options)
+		}
+		log.Printf("No text regions detected, recognizing whole image")
+	}
+	tileMode := preprocess.Crop == customTypes.CropTile || preprocess.Crop == customTypes.CropRegions
+	if tileMode && utils.NeedsTiling(img, customTypes.MAX_IMAGE_DIMENSION) {
+		tiles := utils.TileImage(img, customTypes.MAX_IMAGE_DIMENSION, customTypes.TILE_OVERLAP, customTypes.MAX_IMAGE_TILES)
+		return s.recognizeTiles(ctx, tiles, pipeline, options)
+	}
+
+	log.Printf("Preprocessing image for OCR: %v", pipeline.Stages())
+	result, _, err := s.recognizeProcessed(ctx, img, pipeline, options)
+	return result, err
+}
+
+// recognizeProcessed는 파이프라인을 적용한 이미지를 PNG로 인코딩해 OCR 엔진으로 인식합니다.
+// 좌표 변환에 쓸 수 있도록 전처리 후 이미지 영역을 함께 반환합니다.
+func (s *OcrService) recognizeProcessed(ctx context.Context, img image.Image, pipeline *utils.ImagePipeline, options customTypes.OcrOptions) (*customTypes.OcrEngineResult, image.Rectangle, error) {
+	processed, err := pipeline.Apply(img)
+	if err != nil {
+		return nil, image.Rectangle{}, err
+	}
+
+	encoded, err := utils.EncodePNG(processed)
+	if err != nil {
+		return nil, image.Rectangle{}, err
+	}
+
+	// OCR 엔진 실행
+	result, err := s.engine.Recognize(ctx, encoded, options)
+	if err != nil {
+		return nil, image.Rectangle{}, err
+	}
+	return result, processed.Bounds(), nil
+}
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"reflect"
+
+	customTypes "github.com/ndns-dev/ndns-tesseract/src/types"
+	"github.com/ndns-dev/ndns-tesseract/src/utils"
+)
+
+// recognitionPlan은 이미지 하나를 인식할 때 적용할 전처리와 OCR 옵션입니다.
+// Variants가 있으면 각 변형을 병렬로 실행해 가장 점수가 높은 결과를 사용합니다.
+type recognitionPlan struct {
+	Preprocess customTypes.PreprocessOptions `json:"preprocess"`
+	Ocr        customTypes.OcrOptions        `json:"ocr"`
+	Variants   []recognitionVariant          `json:"variants,omitempty"`
+}
+
+// recognitionVariant는 요청 옵션과 합쳐 확정된 다중 패스 변형입니다.
+type recognitionVariant struct {
+	Name       string                        `json:"name"`
+	Preprocess customTypes.PreprocessOptions `json:"preprocess"`
+	Ocr        customTypes.OcrOptions        `json:"ocr"`
+}
+
+// newRecognitionPlan은 요청에 지정된 옵션으로 인식 계획을 만듭니다.
+// OCR 옵션은 기본값, 요청 공통 옵션(ocr), 위치별 옵션(positionOcr) 순으로 덮어씁니다.
+// 요청에 변형이 없으면 서비스에 설정된 defaultVariants를 사용합니다.
+func newRecognitionPlan(queueState customTypes.OcrQueueState, position customTypes.OcrPosition, defaultVariants []customTypes.OcrVariant) recognitionPlan {
+	plan := recognitionPlan{
+		Ocr: customTypes.DefaultOcrOptions().
+			Merge(queueState.Ocr).
+			Merge(queueState.PositionOcr[position]),
+	}
+	if queueState.Preprocess != nil {
+		plan.Preprocess = *queueState.Preprocess
+	}
+
+	variants := queueState.Variants
+	if len(variants) == 0 {
+		variants = defaultVariants
+	}
+	for _, variant := range variants {
+		resolved := recognitionVariant{
+			Name:       variant.Name,
+			Preprocess: plan.Preprocess,
+			Ocr:        plan.Ocr.Merge(variant.Ocr),
+		}
+		if variant.Preprocess != nil {
+			resolved.Preprocess = *variant.Preprocess
+		}
+		plan.Variants = append(plan.Variants, resolved)
+	}
+	return plan
+}
+
+// Key는 캐시 구분용 키를 반환합니다. 기본 설정이면 기존 결과와 호환되도록 빈 문자열입니다.
+func (p recognitionPlan) Key() string {
+	defaults := recognitionPlan{Ocr: customTypes.DefaultOcrOptions()}
+	if reflect.DeepEqual(p, defaults) {
+		return ""
+	}
+	encoded, _ := json.Marshal(p)
+	sum := sha256.Sum256(encoded)
+	return hex.EncodeToString(sum[:8])
+}
+
+// validateQueueState는 queueState를 mode로 처리할 요청으로 보고 OcrQueueState.Validate로 검증합니다.
+// 오류는 필드별 FieldErrors를 담은 VALIDATION_FAILED 코드의 AppError입니다.
+func validateQueueState(queueState customTypes.OcrQueueState, mode customTypes.OcrJobMode) error {
+	queueState.Mode = mode
+	return utils.NewError(customTypes.ErrorCodeValidation, queueState.Validate())
+}
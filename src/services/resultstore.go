@@ -0,0 +1,445 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	ddbTypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	customTypes "github.com/ndns-dev/ndns-tesseract/src/types"
+	"github.com/ndns-dev/ndns-tesseract/src/utils"
+)
+
+// ResultStore는 OCR 결과 저장소입니다. 결과는 이미지 URL 하나에 최신 결과 하나가 저장됩니다.
+type ResultStore interface {
+	// Save는 결과를 저장합니다. 같은 이미지 URL의 기존 결과는 (PipelineVersion, EngineVersion) 순서로
+	// 같거나 낮을 때만 덮어쓰고, 더 높은 버전의 결과가 있으면 저장하지 않고 ErrStaleResult를 반환합니다.
+	Save(ctx context.Context, result customTypes.OcrResult) error
+	// GetByImageUrl은 이미지 URL의 결과를 조회합니다. 없으면 nil을 반환합니다.
+	GetByImageUrl(ctx context.Context, imageUrl string) (*customTypes.OcrResult, error)
+	// ListByJobId는 작업의 결과를 position 순으로 한 페이지씩 조회합니다.
+	// 같은 위치의 결과끼리의 순서는 정해져 있지 않습니다 (JobIdIndex의 정렬 키는 position뿐입니다).
+	ListByJobId(ctx context.Context, jobId string, query ResultQuery) (*ResultPage, error)
+}
+
+// ErrStaleResult는 더 높은 파이프라인 또는 엔진 버전의 결과가 이미 저장되어 있어 저장하지 않았음을 나타냅니다.
+var ErrStaleResult = errors.New("a result from a newer pipeline or engine version is already stored")
+
+// engineVersionKey는 엔진 버전을 문자열 비교로 순서를 정할 수 있게 숫자 부분을 0으로 채운 키로 바꿉니다.
+// 예: "tesseract 5.10.0" → "tesseract 000005.000010.000000". 버전을 모르면 가장 낮은 빈 키입니다.
+func engineVersionKey(version string) string {
+	var key strings.Builder
+	digits := 0
+	flush := func(end int) {
+		if digits > 0 {
+			key.WriteString(strings.Repeat("0", max(0, 6-digits)))
+			key.WriteString(version[end-digits : end])
+			digits = 0
+		}
+	}
+	for i := 0; i < len(version); i++ {
+		if c := version[i]; c >= '0' && c <= '9' {
+			digits++
+			continue
+		}
+		flush(i)
+		key.WriteByte(version[i])
+	}
+	flush(len(version))
+	return key.String()
+}
+
+// newerThan은 a가 b보다 높은 (PipelineVersion, EngineVersion) 순서인지 확인합니다.
+func newerThan(a, b customTypes.OcrResult) bool {
+	if a.PipelineVersion != b.PipelineVersion {
+		return a.PipelineVersion > b.PipelineVersion
+	}
+	return engineVersionKey(a.EngineVersion) > engineVersionKey(b.EngineVersion)
+}
+
+// 결과 목록 페이지 크기
+const (
+	DefaultResultPageSize = 50
+	MaxResultPageSize     = 500
+)
+
+// DefaultOcrResultTTL은 저장한 결과가 DynamoDB TTL로 삭제되기까지의 기본 기간입니다.
+const DefaultOcrResultTTL = 90 * 24 * time.Hour
+
+// resultTTLFromEnv는 OCR_RESULT_TTL(Go duration)로 결과 만료 기간을 정합니다. 0이면 만료하지 않습니다.
+func resultTTLFromEnv() time.Duration {
+	raw := os.Getenv("OCR_RESULT_TTL")
+	if raw == "" {
+		return DefaultOcrResultTTL
+	}
+	v, err := time.ParseDuration(raw)
+	if err != nil || v < 0 {
+		log.Printf("WARNING: Invalid OCR_RESULT_TTL %q, using %s", raw, DefaultOcrResultTTL)
+		return DefaultOcrResultTTL
+	}
+	return v
+}
+
+// ResultQuery는 작업별 결과 조회 조건입니다.
+type ResultQuery struct {
+	Position customTypes.OcrPosition // 비어 있지 않으면 해당 위치의 결과만 조회
+	Limit    int                     // 페이지 크기 (0이면 DefaultResultPageSize, 최대 MaxResultPageSize)
+	Cursor   string                  // 이전 페이지의 NextCursor (비어 있으면 처음부터)
+}
+
+// pageSize는 유효 범위로 보정한 페이지 크기를 반환합니다.
+func (q ResultQuery) pageSize() int {
+	if q.Limit <= 0 {
+		return DefaultResultPageSize
+	}
+	return min(q.Limit, MaxResultPageSize)
+}
+
+// ResultPage는 결과 목록 한 페이지입니다. NextCursor가 비어 있으면 마지막 페이지입니다.
+type ResultPage struct {
+	Results    []customTypes.OcrResult `json:"results"`
+	NextCursor string                  `json:"nextCursor,omitempty"`
+}
+
+// resultCursor는 페이지 커서에 담기는 마지막 항목의 키입니다.
+// DynamoDB GSI의 LastEvaluatedKey(GSI 키 jobId, position과 테이블 키 imageUrl)와 같은 구성입니다.
+type resultCursor struct {
+	JobId    string `json:"jobId" dynamodbav:"jobId"`
+	Position string `json:"position" dynamodbav:"position"`
+	ImageUrl string `json:"imageUrl" dynamodbav:"imageUrl"`
+}
+
+// encodeResultCursor는 커서를 URL에 그대로 쓸 수 있는 문자열로 만듭니다.
+func encodeResultCursor(cursor resultCursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeResultCursor는 커서 문자열을 해석합니다. 다른 작업의 커서이거나 형식이 잘못되면 VALIDATION_FAILED 오류입니다.
+func decodeResultCursor(raw, jobId string) (*resultCursor, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	invalid := utils.NewError(customTypes.ErrorCodeValidation, customTypes.FieldErrors{{Field: "cursor", Message: "invalid cursor"}})
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, invalid
+	}
+	var cursor resultCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.JobId != jobId || cursor.ImageUrl == "" {
+		return nil, invalid
+	}
+	return &cursor, nil
+}
+
+// NewResultStoreFromEnv는 RESULT_STORE 설정으로 결과 저장소를 생성합니다.
+// dynamodb(기본값), memory, file(RESULT_STORE_FILE 경로의 JSON 파일)을 지원합니다.
+// 파일을 불러오지 못하면 경고를 남기고 메모리 저장소를 사용합니다.
+func NewResultStoreFromEnv() ResultStore {
+	switch kind := strings.ToLower(os.Getenv("RESULT_STORE")); kind {
+	case "", "dynamodb":
+		return NewDynamoResultStore(customTypes.OcrResultTableName)
+	case "memory":
+		return NewMemoryResultStore()
+	case "file":
+		path := os.Getenv("RESULT_STORE_FILE")
+		if path == "" {
+			path = DefaultResultStoreFile
+		}
+		store, err := NewFileResultStore(path)
+		if err != nil {
+			log.Printf("WARNING: Failed to open result store file %s, using memory: %v", path, err)
+			return NewMemoryResultStore()
+		}
+		return store
+	default:
+		log.Printf("WARNING: Unknown RESULT_STORE %q, using dynamodb", kind)
+		return NewDynamoResultStore(customTypes.OcrResultTableName)
+	}
+}
+
+// dynamoDBClient는 DynamoDB 클라이언트를 가져오고, 실패하면 PERSISTENCE_FAILED 오류를 반환합니다.
+func dynamoDBClient(ctx context.Context) (*dynamodb.Client, error) {
+	client, err := utils.GetDynamoDBClient(ctx)
+	if err != nil {
+		return nil, utils.NewError(customTypes.ErrorCodePersistence, err)
+	}
+	return client, nil
+}
+
+// DynamoResultStore는 OcrResult 테이블(imageUrl 파티션 키)에 결과를 저장합니다.
+type DynamoResultStore struct {
+	table customTypes.TableName
+}
+
+// NewDynamoResultStore는 테이블 이름으로 저장소를 생성합니다.
+func NewDynamoResultStore(table customTypes.TableName) *DynamoResultStore {
+	return &DynamoResultStore{table: table}
+}
+
+// Save는 기존 결과의 pipelineVersion이 낮거나, 같으면서 engineVersionKey가 같거나 낮을 때만 PutItem으로 저장합니다.
+// 배포 중 이전 엔진을 쓰는 컨테이너가 새 엔진의 결과를 덮어쓰지 않도록 engineVersionKey 속성을 함께 저장합니다.
+func (d *DynamoResultStore) Save(ctx context.Context, result customTypes.OcrResult) error {
+	client, err := dynamoDBClient(ctx)
+	if err != nil {
+		return err
+	}
+	item, err := attributevalue.MarshalMap(result)
+	if err != nil {
+		return fmt.Errorf("failed to marshal DynamoDB item: %w", err)
+	}
+	engineKey := &ddbTypes.AttributeValueMemberS{Value: engineVersionKey(result.EngineVersion)}
+	item["engineVersionKey"] = engineKey
+	_, err = client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(string(d.table)),
+		Item:      item,
+		ConditionExpression: aws.String("attribute_not_exists(imageUrl) OR attribute_not_exists(pipelineVersion) OR " +
+			"pipelineVersion < :pipelineVersion OR (pipelineVersion = :pipelineVersion AND " +
+			"(attribute_not_exists(engineVersionKey) OR engineVersionKey <= :engineVersionKey))"),
+		ExpressionAttributeValues: map[string]ddbTypes.AttributeValue{
+			":pipelineVersion":  &ddbTypes.AttributeValueMemberN{Value: strconv.Itoa(result.PipelineVersion)},
+			":engineVersionKey": engineKey,
+		},
+	})
+	if err != nil {
+		var conditionErr *ddbTypes.ConditionalCheckFailedException
+		if errors.As(err, &conditionErr) {
+			return fmt.Errorf("%s (pipeline version %d, engine %q): %w", result.ImageUrl, result.PipelineVersion, result.EngineVersion, ErrStaleResult)
+		}
+		return utils.Errorf(customTypes.ErrorCodePersistence, "failed to save to DynamoDB: %w", err)
+	}
+	return nil
+}
+
+// GetByImageUrl은 GetItem으로 결과를 조회합니다.
+func (d *DynamoResultStore) GetByImageUrl(ctx context.Context, imageUrl string) (*customTypes.OcrResult, error) {
+	client, err := dynamoDBClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out, err := client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(string(d.table)),
+		Key: map[string]ddbTypes.AttributeValue{
+			"imageUrl": &ddbTypes.AttributeValueMemberS{Value: imageUrl},
+		},
+	})
+	if err != nil {
+		return nil, utils.Errorf(customTypes.ErrorCodePersistence, "failed to get OCR result %s: %w", imageUrl, err)
+	}
+	if len(out.Item) == 0 {
+		return nil, nil
+	}
+
+	var result customTypes.OcrResult
+	if err := attributevalue.UnmarshalMap(out.Item, &result); err != nil {
+		return nil, utils.Errorf(customTypes.ErrorCodePersistence, "failed to read OCR result %s: %w", imageUrl, err)
+	}
+	return &result, nil
+}
+
+// ListByJobId는 JobIdIndex GSI를 조회합니다. GSI는 최종적 일관성이므로 방금 저장한 결과가 바로 보이지 않을 수 있습니다.
+func (d *DynamoResultStore) ListByJobId(ctx context.Context, jobId string, query ResultQuery) (*ResultPage, error) {
+	cursor, err := decodeResultCursor(query.Cursor, jobId)
+	if err != nil {
+		return nil, err
+	}
+	client, err := dynamoDBClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(string(d.table)),
+		IndexName:              aws.String(customTypes.OcrResultJobIdIndexName),
+		KeyConditionExpression: aws.String("jobId = :jobId"),
+		ExpressionAttributeValues: map[string]ddbTypes.AttributeValue{
+			":jobId": &ddbTypes.AttributeValueMemberS{Value: jobId},
+		},
+		Limit: aws.Int32(int32(query.pageSize())),
+	}
+	if query.Position != "" {
+		input.KeyConditionExpression = aws.String("jobId = :jobId AND #position = :position")
+		input.ExpressionAttributeNames = map[string]string{"#position": "position"}
+		input.ExpressionAttributeValues[":position"] = &ddbTypes.AttributeValueMemberS{Value: string(query.Position)}
+	}
+	if cursor != nil {
+		input.ExclusiveStartKey = map[string]ddbTypes.AttributeValue{
+			"jobId":    &ddbTypes.AttributeValueMemberS{Value: cursor.JobId},
+			"position": &ddbTypes.AttributeValueMemberS{Value: cursor.Position},
+			"imageUrl": &ddbTypes.AttributeValueMemberS{Value: cursor.ImageUrl},
+		}
+	}
+
+	out, err := client.Query(ctx, input)
+	if err != nil {
+		return nil, utils.Errorf(customTypes.ErrorCodePersistence, "failed to list OCR results for job %s: %w", jobId, err)
+	}
+	page := &ResultPage{Results: []customTypes.OcrResult{}}
+	if err := attributevalue.UnmarshalListOfMaps(out.Items, &page.Results); err != nil {
+		return nil, utils.Errorf(customTypes.ErrorCodePersistence, "failed to read OCR results for job %s: %w", jobId, err)
+	}
+	if len(out.LastEvaluatedKey) > 0 {
+		var next resultCursor
+		if err := attributevalue.UnmarshalMap(out.LastEvaluatedKey, &next); err != nil {
+			return nil, utils.Errorf(customTypes.ErrorCodePersistence, "failed to read page key for job %s: %w", jobId, err)
+		}
+		page.NextCursor = encodeResultCursor(next)
+	}
+	return page, nil
+}
+
+// DefaultResultStoreFile은 RESULT_STORE=file일 때 경로를 지정하지 않으면 사용하는 파일입니다.
+const DefaultResultStoreFile = "ocr-results.json"
+
+// MemoryResultStore는 프로세스 메모리에 결과를 저장하는 ResultStore입니다.
+// 파일 경로가 있으면 저장할 때마다 전체 결과를 JSON 파일로 기록해 재시작 후에도 유지합니다. 로컬 실행과 테스트용입니다.
+type MemoryResultStore struct {
+	mu      sync.RWMutex
+	results map[string]customTypes.OcrResult
+	path    string
+}
+
+// NewMemoryResultStore는 빈 메모리 저장소를 생성합니다.
+func NewMemoryResultStore() *MemoryResultStore {
+	return &MemoryResultStore{results: make(map[string]customTypes.OcrResult)}
+}
+
+// NewFileResultStore는 path의 JSON 파일을 불러와 파일 기반 저장소를 생성합니다. 파일이 없으면 빈 상태로 시작합니다.
+func NewFileResultStore(path string) (*MemoryResultStore, error) {
+	store := NewMemoryResultStore()
+	store.path = path
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var results []customTypes.OcrResult
+	if err := json.Unmarshal(data, &results); err != nil {
+		return nil, fmt.Errorf("invalid result store file: %w", err)
+	}
+	for _, result := range results {
+		store.results[result.ImageUrl] = result
+	}
+	return store, nil
+}
+
+// Save는 DynamoResultStore와 같은 버전 규칙으로 결과를 저장하고, 파일 경로가 있으면 파일에도 기록합니다.
+func (m *MemoryResultStore) Save(ctx context.Context, result customTypes.OcrResult) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if existing, ok := m.results[result.ImageUrl]; ok && newerThan(existing, result) {
+		return fmt.Errorf("%s (pipeline version %d, engine %q): %w", result.ImageUrl, result.PipelineVersion, result.EngineVersion, ErrStaleResult)
+	}
+	m.results[result.ImageUrl] = result
+	if m.path == "" {
+		return nil
+	}
+	if err := m.writeFile(); err != nil {
+		return utils.Errorf(customTypes.ErrorCodePersistence, "failed to write result store file: %w", err)
+	}
+	return nil
+}
+
+// writeFile은 모든 결과를 임시 파일에 쓴 뒤 이름을 바꿔 원자적으로 교체합니다. 호출자가 잠금을 가지고 있어야 합니다.
+func (m *MemoryResultStore) writeFile() error {
+	results := make([]customTypes.OcrResult, 0, len(m.results))
+	for _, result := range m.results {
+		results = append(results, result)
+	}
+	sort.Slice(results, func(a, b int) bool { return results[a].ImageUrl < results[b].ImageUrl })
+	data, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(m.path), filepath.Base(m.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), m.path)
+}
+
+// GetByImageUrl은 이미지 URL의 결과를 조회합니다.
+func (m *MemoryResultStore) GetByImageUrl(ctx context.Context, imageUrl string) (*customTypes.OcrResult, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	result, ok := m.results[imageUrl]
+	if !ok {
+		return nil, nil
+	}
+	return &result, nil
+}
+
+// ListByJobId는 DynamoResultStore와 같은 커서 형식으로 결과를 position 순으로 조회합니다.
+// 같은 위치의 결과는 커서로 이어 읽을 수 있도록 imageUrl 순으로 정렬하지만 인터페이스가 보장하는 순서는 아닙니다.
+func (m *MemoryResultStore) ListByJobId(ctx context.Context, jobId string, query ResultQuery) (*ResultPage, error) {
+	cursor, err := decodeResultCursor(query.Cursor, jobId)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.RLock()
+	var matched []customTypes.OcrResult
+	for _, result := range m.results {
+		if result.JobId != jobId || (query.Position != "" && result.Position != query.Position) {
+			continue
+		}
+		if cursor != nil && !resultAfter(result, *cursor) {
+			continue
+		}
+		matched = append(matched, result)
+	}
+	m.mu.RUnlock()
+
+	sort.Slice(matched, func(a, b int) bool {
+		if matched[a].Position != matched[b].Position {
+			return matched[a].Position < matched[b].Position
+		}
+		return matched[a].ImageUrl < matched[b].ImageUrl
+	})
+
+	page := &ResultPage{Results: matched}
+	if size := query.pageSize(); len(matched) > size {
+		page.Results = matched[:size]
+		last := page.Results[size-1]
+		page.NextCursor = encodeResultCursor(resultCursor{JobId: jobId, Position: string(last.Position), ImageUrl: last.ImageUrl})
+	}
+	if page.Results == nil {
+		page.Results = []customTypes.OcrResult{}
+	}
+	return page, nil
+}
+
+// resultAfter는 결과가 커서 위치보다 뒤에 있는지 확인합니다.
+func resultAfter(result customTypes.OcrResult, cursor resultCursor) bool {
+	if string(result.Position) != cursor.Position {
+		return string(result.Position) > cursor.Position
+	}
+	return result.ImageUrl > cursor.ImageUrl
+}
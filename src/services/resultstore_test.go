@@ -0,0 +1,258 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	customTypes "github.com/ndns-dev/ndns-tesseract/src/types"
+)
+
+// seedResults는 job-1에 위치별 결과를, job-2에 결과 하나를 저장합니다.
+func seedResults(t *testing.T, store ResultStore) []customTypes.OcrResult {
+	t.Helper()
+	var job1 []customTypes.OcrResult
+	for _, position := range []customTypes.OcrPosition{customTypes.OcrPositionLastImage, customTypes.OcrPositionFirstImage} {
+		for i := 0; i < 3; i++ {
+			result := customTypes.OcrResult{
+				ImageUrl:        fmt.Sprintf("https://img/%s/%d", position, i),
+				JobId:           "job-1",
+				Position:        position,
+				PipelineVersion: customTypes.OcrPipelineVersion,
+			}
+			if err := store.Save(context.Background(), result); err != nil {
+				t.Fatal(err)
+			}
+			job1 = append(job1, result)
+		}
+	}
+	other := customTypes.OcrResult{ImageUrl: "https://img/other", JobId: "job-2", Position: customTypes.OcrPositionFirstImage}
+	if err := store.Save(context.Background(), other); err != nil {
+		t.Fatal(err)
+	}
+	return job1
+}
+
+// listAll은 커서를 따라가며 모든 페이지의 이미지 URL을 모읍니다.
+func listAll(t *testing.T, store ResultStore, jobId string, query ResultQuery) ([]string, int) {
+	t.Helper()
+	var urls []string
+	pages := 0
+	for {
+		page, err := store.ListByJobId(context.Background(), jobId, query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pages++
+		for _, result := range page.Results {
+			urls = append(urls, result.ImageUrl)
+		}
+		if page.NextCursor == "" {
+			return urls, pages
+		}
+		query.Cursor = page.NextCursor
+	}
+}
+
+func TestMemoryResultStoreListByJobId(t *testing.T) {
+	store := NewMemoryResultStore()
+	seedResults(t, store)
+
+	tests := []struct {
+		name      string
+		query     ResultQuery
+		wantUrls  []string
+		wantPages int
+	}{
+		{
+			name:  "single page ordered by position",
+			query: ResultQuery{},
+			wantUrls: []string{
+				"https://img/FirstImageUrl/0", "https://img/FirstImageUrl/1", "https://img/FirstImageUrl/2",
+				"https://img/LastImageUrl/0", "https://img/LastImageUrl/1", "https://img/LastImageUrl/2",
+			},
+			wantPages: 1,
+		},
+		{
+			name:  "pages of two",
+			query: ResultQuery{Limit: 2},
+			wantUrls: []string{
+				"https://img/FirstImageUrl/0", "https://img/FirstImageUrl/1", "https://img/FirstImageUrl/2",
+				"https://img/LastImageUrl/0", "https://img/LastImageUrl/1", "https://img/LastImageUrl/2",
+			},
+			wantPages: 3,
+		},
+		{
+			name:      "one position",
+			query:     ResultQuery{Position: customTypes.OcrPositionLastImage, Limit: 2},
+			wantUrls:  []string{"https://img/LastImageUrl/0", "https://img/LastImageUrl/1", "https://img/LastImageUrl/2"},
+			wantPages: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			urls, pages := listAll(t, store, "job-1", tt.query)
+			if fmt.Sprint(urls) != fmt.Sprint(tt.wantUrls) {
+				t.Errorf("urls = %v, want %v", urls, tt.wantUrls)
+			}
+			if pages != tt.wantPages {
+				t.Errorf("pages = %d, want %d", pages, tt.wantPages)
+			}
+		})
+	}
+
+	page, err := store.ListByJobId(context.Background(), "missing", ResultQuery{})
+	if err != nil || page.Results == nil || len(page.Results) != 0 || page.NextCursor != "" {
+		t.Errorf("empty job page = %+v, %v", page, err)
+	}
+}
+
+func TestResultCursorValidation(t *testing.T) {
+	store := NewMemoryResultStore()
+	seedResults(t, store)
+	page, err := store.ListByJobId(context.Background(), "job-1", ResultQuery{Limit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, cursor := range map[string]string{
+		"not base64":       "!!!",
+		"not JSON":         "bm90IGpzb24",
+		"another job":      encodeResultCursor(resultCursor{JobId: "job-2", Position: "FirstImageUrl", ImageUrl: "x"}),
+		"missing imageUrl": encodeResultCursor(resultCursor{JobId: "job-1", Position: "FirstImageUrl"}),
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := store.ListByJobId(context.Background(), "job-1", ResultQuery{Cursor: cursor})
+			wantCode(t, err, customTypes.ErrorCodeValidation)
+		})
+	}
+
+	// 다른 작업에 발급된 커서는 거부됩니다.
+	_, err = store.ListByJobId(context.Background(), "job-2", ResultQuery{Cursor: page.NextCursor})
+	wantCode(t, err, customTypes.ErrorCodeValidation)
+}
+
+func TestResultQueryPageSize(t *testing.T) {
+	for limit, want := range map[int]int{-1: DefaultResultPageSize, 0: DefaultResultPageSize, 10: 10, MaxResultPageSize + 1: MaxResultPageSize} {
+		if got := (ResultQuery{Limit: limit}).pageSize(); got != want {
+			t.Errorf("pageSize(%d) = %d, want %d", limit, got, want)
+		}
+	}
+}
+
+func TestMemoryResultStorePipelineVersion(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryResultStore()
+	newer := customTypes.OcrResult{ImageUrl: "https://img/1", OcrText: "new", PipelineVersion: 2}
+	if err := store.Save(ctx, newer); err != nil {
+		t.Fatal(err)
+	}
+
+	older := customTypes.OcrResult{ImageUrl: "https://img/1", OcrText: "old", PipelineVersion: 1}
+	if err := store.Save(ctx, older); !errors.Is(err, ErrStaleResult) {
+		t.Fatalf("err = %v, want ErrStaleResult", err)
+	}
+	same := customTypes.OcrResult{ImageUrl: "https://img/1", OcrText: "again", PipelineVersion: 2}
+	if err := store.Save(ctx, same); err != nil {
+		t.Fatal(err)
+	}
+
+	stored, err := store.GetByImageUrl(ctx, "https://img/1")
+	if err != nil || stored.OcrText != "again" {
+		t.Errorf("stored = %+v, %v", stored, err)
+	}
+}
+
+func TestFileResultStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "results.json")
+	store, err := NewFileResultStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved := seedResults(t, store)
+
+	reopened, err := NewFileResultStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range saved {
+		got, err := reopened.GetByImageUrl(context.Background(), want.ImageUrl)
+		if err != nil || got == nil || got.JobId != want.JobId || got.Position != want.Position {
+			t.Errorf("GetByImageUrl(%s) = %+v, %v", want.ImageUrl, got, err)
+		}
+	}
+
+	if _, err := NewFileResultStore(t.TempDir()); err == nil {
+		t.Error("expected an error for a directory path")
+	}
+
+	// 디렉토리가 없어 기록할 수 없으면 PERSISTENCE_FAILED입니다.
+	unwritable, err := NewFileResultStore(filepath.Join(t.TempDir(), "missing-dir", "results.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = unwritable.Save(context.Background(), customTypes.OcrResult{ImageUrl: "https://img/1"})
+	wantCode(t, err, customTypes.ErrorCodePersistence)
+}
+
+func TestEngineVersionKey(t *testing.T) {
+	ordered := []string{"", "tesseract 4.1.1", "tesseract 5.3.4", "tesseract 5.10.0"}
+	for i := 1; i < len(ordered); i++ {
+		if a, b := engineVersionKey(ordered[i-1]), engineVersionKey(ordered[i]); a >= b {
+			t.Errorf("key(%q) = %q should sort before key(%q) = %q", ordered[i-1], a, ordered[i], b)
+		}
+	}
+	if got := engineVersionKey("tesseract 5.3.4"); got != "tesseract 000005.000003.000004" {
+		t.Errorf("engineVersionKey = %q", got)
+	}
+}
+
+func TestMemoryResultStoreEngineVersion(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name      string
+		existing  customTypes.OcrResult
+		incoming  customTypes.OcrResult
+		wantStale bool
+	}{
+		{
+			name:      "older engine, same pipeline",
+			existing:  customTypes.OcrResult{PipelineVersion: 1, EngineVersion: "tesseract 5.10.0"},
+			incoming:  customTypes.OcrResult{PipelineVersion: 1, EngineVersion: "tesseract 5.3.4"},
+			wantStale: true,
+		},
+		{
+			name:     "newer engine, same pipeline",
+			existing: customTypes.OcrResult{PipelineVersion: 1, EngineVersion: "tesseract 5.3.4"},
+			incoming: customTypes.OcrResult{PipelineVersion: 1, EngineVersion: "tesseract 5.10.0"},
+		},
+		{
+			name:     "older engine, newer pipeline",
+			existing: customTypes.OcrResult{PipelineVersion: 1, EngineVersion: "tesseract 5.10.0"},
+			incoming: customTypes.OcrResult{PipelineVersion: 2, EngineVersion: "tesseract 5.3.4"},
+		},
+		{
+			name:      "unknown engine does not replace a known one",
+			existing:  customTypes.OcrResult{PipelineVersion: 1, EngineVersion: "tesseract 5.3.4"},
+			incoming:  customTypes.OcrResult{PipelineVersion: 1},
+			wantStale: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := NewMemoryResultStore()
+			tt.existing.ImageUrl, tt.incoming.ImageUrl = "https://img/1", "https://img/1"
+			if err := store.Save(ctx, tt.existing); err != nil {
+				t.Fatal(err)
+			}
+			err := store.Save(ctx, tt.incoming)
+			if got := errors.Is(err, ErrStaleResult); got != tt.wantStale {
+				t.Errorf("stale = %t (%v), want %t", got, err, tt.wantStale)
+			}
+		})
+	}
+}
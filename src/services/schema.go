@@ -0,0 +1,128 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	ddbTypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	customTypes "github.com/ndns-dev/ndns-tesseract/src/types"
+)
+
+// tableActiveTimeout은 테이블이 ACTIVE가 될 때까지 기다리는 최대 시간입니다.
+const tableActiveTimeout = 2 * time.Minute
+
+// TableDefinitions는 서비스가 사용하는 DynamoDB 테이블 스키마를 반환합니다.
+//   - OcrResult: imageUrl 파티션 키, JobIdIndex GSI(jobId 파티션 키, position 정렬 키)
+//   - OcrQueueStatus: jobId 파티션 키, position 정렬 키
+func TableDefinitions() []dynamodb.CreateTableInput {
+	stringAttribute := func(name string) ddbTypes.AttributeDefinition {
+		return ddbTypes.AttributeDefinition{AttributeName: aws.String(name), AttributeType: ddbTypes.ScalarAttributeTypeS}
+	}
+	keySchema := func(hash, rangeKey string) []ddbTypes.KeySchemaElement {
+		keys := []ddbTypes.KeySchemaElement{{AttributeName: aws.String(hash), KeyType: ddbTypes.KeyTypeHash}}
+		if rangeKey != "" {
+			keys = append(keys, ddbTypes.KeySchemaElement{AttributeName: aws.String(rangeKey), KeyType: ddbTypes.KeyTypeRange})
+		}
+		return keys
+	}
+
+	return []dynamodb.CreateTableInput{
+		{
+			TableName: aws.String(string(customTypes.OcrResultTableName)),
+			AttributeDefinitions: []ddbTypes.AttributeDefinition{
+				stringAttribute("imageUrl"),
+				stringAttribute("jobId"),
+				stringAttribute("position"),
+			},
+			KeySchema: keySchema("imageUrl", ""),
+			GlobalSecondaryIndexes: []ddbTypes.GlobalSecondaryIndex{{
+				IndexName:  aws.String(customTypes.OcrResultJobIdIndexName),
+				KeySchema:  keySchema("jobId", "position"),
+				Projection: &ddbTypes.Projection{ProjectionType: ddbTypes.ProjectionTypeAll},
+			}},
+			BillingMode: ddbTypes.BillingModePayPerRequest,
+		},
+		{
+			TableName: aws.String(string(customTypes.OcrQueueStatusTableName)),
+			AttributeDefinitions: []ddbTypes.AttributeDefinition{
+				stringAttribute("jobId"),
+				stringAttribute("position"),
+			},
+			KeySchema:   keySchema("jobId", "position"),
+			BillingMode: ddbTypes.BillingModePayPerRequest,
+		},
+	}
+}
+
+// EnsureTables는 TableDefinitions의 테이블이 없으면 만들고 모두 ACTIVE가 될 때까지 기다립니다.
+// 이미 있는 테이블은 그대로 두고(스키마를 바꾸지 않음) OcrResult 테이블의 expiresAt TTL만 켭니다. DYNAMODB_ENDPOINT로 DynamoDB Local에 연결해
+// 통합 테스트와 로컬 실행에 필요한 테이블을 준비하는 용도입니다.
+func EnsureTables(ctx context.Context) error {
+	client, err := dynamoDBClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	waiter := dynamodb.NewTableExistsWaiter(client)
+	for _, definition := range TableDefinitions() {
+		name := aws.ToString(definition.TableName)
+		_, err := client.CreateTable(ctx, &definition)
+		var inUse *ddbTypes.ResourceInUseException
+		switch {
+		case err == nil:
+			log.Printf("Creating DynamoDB table %s", name)
+		case errors.As(err, &inUse):
+			log.Printf("DynamoDB table %s already exists", name)
+		default:
+			return fmt.Errorf("failed to create table %s: %w", name, err)
+		}
+
+		if err := waiter.Wait(ctx, &dynamodb.DescribeTableInput{TableName: definition.TableName}, tableActiveTimeout); err != nil {
+			return fmt.Errorf("table %s did not become active: %w", name, err)
+		}
+		if attribute, ok := tableTTLAttributes[name]; ok {
+			if err := ensureTimeToLive(ctx, client, name, attribute); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// tableTTLAttributes는 DynamoDB TTL로 만료시킬 테이블과 만료 시각(유닉스 초) 속성입니다.
+var tableTTLAttributes = map[string]string{
+	string(customTypes.OcrResultTableName): "expiresAt",
+}
+
+// ensureTimeToLive는 테이블의 TTL이 꺼져 있으면 attribute로 켭니다.
+func ensureTimeToLive(ctx context.Context, client *dynamodb.Client, table, attribute string) error {
+	described, err := client.DescribeTimeToLive(ctx, &dynamodb.DescribeTimeToLiveInput{TableName: aws.String(table)})
+	if err != nil {
+		return fmt.Errorf("failed to describe TTL of table %s: %w", table, err)
+	}
+	if description := described.TimeToLiveDescription; description != nil {
+		switch description.TimeToLiveStatus {
+		case ddbTypes.TimeToLiveStatusEnabled, ddbTypes.TimeToLiveStatusEnabling:
+			log.Printf("TTL of DynamoDB table %s already uses %s", table, aws.ToString(description.AttributeName))
+			return nil
+		}
+	}
+
+	_, err = client.UpdateTimeToLive(ctx, &dynamodb.UpdateTimeToLiveInput{
+		TableName: aws.String(table),
+		TimeToLiveSpecification: &ddbTypes.TimeToLiveSpecification{
+			AttributeName: aws.String(attribute),
+			Enabled:       aws.Bool(true),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to enable TTL on table %s: %w", table, err)
+	}
+	log.Printf("Enabled TTL on DynamoDB table %s using %s", table, attribute)
+	return nil
+}
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"log"
+	"os"
+	"strings"
+	"unicode"
+
+	customTypes "github.com/ndns-dev/ndns-tesseract/src/types"
+	"github.com/ndns-dev/ndns-tesseract/src/utils"
+)
+
+// DefaultHangulDictionary는 OCR 결과가 실제 한국어 문장인지 가늠하기 위한 기본 단어 사전입니다.
+// 블로그 본문과 협찬 고지 이미지에 자주 나오는 어간 위주이며, 조사가 붙은 어절은 접두사로 비교합니다.
+var DefaultHangulDictionary = []string{
+	// 협찬/광고 고지
+	"협찬", "광고", "유료", "제공", "제공받", "지원", "지원받", "원고료", "소정", "업체", "체험단", "대가", "경제적",
+	"무상", "제품", "서비스", "작성", "솔직", "후기", "리뷰", "포스팅", "게시물", "본문", "이벤트", "당첨",
+	// 블로그 일반
+	"오늘", "이번", "정말", "너무", "진짜", "우리", "저희", "여러분", "안녕", "감사", "소개", "방문", "추천",
+	"맛집", "카페", "메뉴", "가격", "위치", "주소", "영업", "시간", "주차", "예약", "전화", "문의", "할인",
+	"구매", "사용", "사진", "내용", "정보", "이용", "가능", "생각", "느낌", "분위기", "직접", "다음", "처음",
+	"마지막", "이웃", "공감", "댓글", "구독", "블로그", "일상", "여행", "음식", "요리", "가족", "친구",
+	"하루", "주말", "매장", "상품", "브랜드", "공식", "링크", "참고", "안내", "확인", "선물", "행사",
+	// 자주 쓰이는 용언 어간
+	"있습니다", "없습니다", "합니다", "했습니다", "되었습니다", "받았습니다", "받아", "하였", "했어요", "해요",
+	"있어요", "좋아요", "좋은", "좋았", "맛있", "예쁜", "새로운", "같아요", "드립니다", "드려요",
+}
+
+// 다중 패스 OCR 점수 가중치
+const (
+	confidenceScoreWeight  = 0.5
+	dictionaryScoreWeight  = 0.5
+	minDictionaryWordRunes = 2
+)
+
+// OcrResultScorer는 평균 단어 신뢰도와 한국어 사전 적중률로 OCR 결과의 품질을 점수화합니다.
+// 다른 PSM이나 전처리로 얻은 결과 가운데 가장 그럴듯한 것을 고르는 데 사용합니다.
+type OcrResultScorer struct {
+	dictionary   map[string]bool
+	maxWordRunes int
+}
+
+// NewOcrResultScorer는 단어 목록으로 점수 계산기를 생성합니다.
+func NewOcrResultScorer(words []string) *OcrResultScorer {
+	s := &OcrResultScorer{dictionary: make(map[string]bool, len(words))}
+	for _, word := range words {
+		word = strings.TrimSpace(word)
+		if len([]rune(word)) < minDictionaryWordRunes {
+			continue
+		}
+		s.dictionary[word] = true
+		s.maxWordRunes = max(s.maxWordRunes, len([]rune(word)))
+	}
+	return s
+}
+
+// NewOcrResultScorerFromEnv는 기본 사전에 OCR_DICTIONARY_FILE(줄 단위)의 단어를 더해 점수 계산기를 생성합니다.
+func NewOcrResultScorerFromEnv() *OcrResultScorer {
+	words := DefaultHangulDictionary
+	if path := os.Getenv("OCR_DICTIONARY_FILE"); path != "" {
+		loaded, err := loadPhrasesFile(path)
+		if err != nil {
+			log.Printf("WARNING: Failed to load OCR dictionary from %s, using defaults: %v", path, err)
+		} else {
+			words = append(append([]string(nil), DefaultHangulDictionary...), loaded...)
+		}
+	}
+	return NewOcrResultScorer(words)
+}
+
+// Score는 0~1 범위의 점수를 반환합니다. 텍스트가 비어 있으면 0입니다.
+func (s *OcrResultScorer) Score(result *customTypes.OcrEngineResult) float64 {
+	if strings.TrimSpace(result.Text) == "" {
+		return 0
+	}
+	confidence := min(max(result.MeanConfidence/100, 0), 1)
+	return confidenceScoreWeight*confidence + dictionaryScoreWeight*s.DictionaryHitRate(result.Text)
+}
+
+// DictionaryHitRate는 한글이 포함된 어절 가운데 사전 단어로 시작하는 어절의 비율을 반환합니다.
+// 한글 어절이 없으면 0입니다.
+func (s *OcrResultScorer) DictionaryHitRate(text string) float64 {
+	total, hits := 0, 0
+	for _, token := range strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		runes := hangulPrefix([]rune(token))
+		if len(runes) == 0 {
+			continue
+		}
+		total++
+		if s.hasDictionaryPrefix(runes) {
+			hits++
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	return float64(hits) / float64(total)
+}
+
+// hasDictionaryPrefix는 어절이 사전 단어로 시작하는지 확인합니다.
+func (s *OcrResultScorer) hasDictionaryPrefix(runes []rune) bool {
+	for n := min(len(runes), s.maxWordRunes); n >= minDictionaryWordRunes; n-- {
+		if s.dictionary[string(runes[:n])] {
+			return true
+		}
+	}
+	return false
+}
+
+// hangulPrefix는 어절 앞쪽의 연속된 한글 음절을 반환합니다. 한글로 시작하지 않으면 빈 슬라이스입니다.
+func hangulPrefix(runes []rune) []rune {
+	end := 0
+	for end < len(runes) && utils.IsHangulSyllable(runes[end]) {
+		end++
+	}
+	return runes[:end]
+}
@@ -0,0 +1,77 @@
+package services
+
+import (
+	"testing"
+
+	customTypes "github.com/ndns-dev/ndns-tesseract/src/types"
+)
+
+func TestDictionaryHitRate(t *testing.T) {
+	scorer := NewOcrResultScorer(DefaultHangulDictionary)
+
+	tests := []struct {
+		name string
+		text string
+		want float64
+	}{
+		{name: "every word in dictionary", text: "소정의 원고료를 받아 작성했습니다", want: 1},
+		{name: "half the words", text: "맛집 뷁뛟 카페 쉛퉯", want: 0.5},
+		{name: "non hangul tokens are ignored", text: "협찬 ABC 123 ㅁㄴㅇ", want: 1},
+		{name: "no hangul", text: "sponsored post", want: 0},
+		{name: "empty", text: "", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := scorer.DictionaryHitRate(tt.text); got != tt.want {
+				t.Errorf("DictionaryHitRate(%q) = %.3f, want %.3f", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOcrResultScorerOrdering(t *testing.T) {
+	scorer := NewOcrResultScorer(DefaultHangulDictionary)
+	result := func(text string, confidence float64) *customTypes.OcrEngineResult {
+		return &customTypes.OcrEngineResult{Text: text, MeanConfidence: confidence}
+	}
+
+	// 앞쪽 결과가 뒤쪽 결과보다 높은 점수를 받아야 합니다.
+	tests := []struct {
+		name          string
+		better, worse *customTypes.OcrEngineResult
+	}{
+		{name: "dictionary words beat gibberish", better: result("소정의 원고료를 받아", 60), worse: result("뷁뛟 쉛퉯 닭뷁", 60)},
+		{name: "higher confidence wins with equal words", better: result("카페 후기", 90), worse: result("카페 후기", 40)},
+		{name: "words outweigh a small confidence gap", better: result("맛집 추천", 70), worse: result("뷁뛟 쉛퉯", 95)},
+		{name: "any text beats empty", better: result("뷁뛟", 10), worse: result("  ", 99)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			better, worse := scorer.Score(tt.better), scorer.Score(tt.worse)
+			if better <= worse {
+				t.Errorf("Score(%q) = %.3f, want more than Score(%q) = %.3f", tt.better.Text, better, tt.worse.Text, worse)
+			}
+		})
+	}
+}
+
+func TestOcrResultScorerRange(t *testing.T) {
+	scorer := NewOcrResultScorer(DefaultHangulDictionary)
+	tests := []struct {
+		name   string
+		result customTypes.OcrEngineResult
+		want   float64
+	}{
+		{name: "perfect", result: customTypes.OcrEngineResult{Text: "협찬 광고", MeanConfidence: 100}, want: 1},
+		{name: "confidence clamped", result: customTypes.OcrEngineResult{Text: "협찬 광고", MeanConfidence: 250}, want: 1},
+		{name: "negative confidence", result: customTypes.OcrEngineResult{Text: "sponsored", MeanConfidence: -1}, want: 0},
+		{name: "empty text", result: customTypes.OcrEngineResult{Text: "", MeanConfidence: 100}, want: 0},
+	}
+	for _, tt := range tests {
+		if got := scorer.Score(&tt.result); got != tt.want {
+			t.Errorf("%s: Score() = %.3f, want %.3f", tt.name, got, tt.want)
+		}
+	}
+}
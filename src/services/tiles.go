@@ -0,0 +1,60 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+
+	customTypes "github.com/ndns-dev/ndns-tesseract/src/types"
+	"github.com/ndns-dev/ndns-tesseract/src/utils"
+)
+
+// DefaultTileConcurrency는 타일을 동시에 인식하는 수의 기본값입니다.
+const DefaultTileConcurrency = 3
+
+// tileConcurrencyFromEnv는 OCR_TILE_CONCURRENCY로 설정된 동시 실행 수를 반환합니다.
+func tileConcurrencyFromEnv() int {
+	if raw := os.Getenv("OCR_TILE_CONCURRENCY"); raw != "" {
+		if v, err := strconv.Atoi(raw); err == nil && v > 0 {
+			return v
+		}
+		log.Printf("WARNING: Invalid OCR_TILE_CONCURRENCY %q, using %d", raw, DefaultTileConcurrency)
+	}
+	return DefaultTileConcurrency
+}
+
+// recognizeTiles는 타일(큰 이미지의 조각 또는 텍스트 영역)을 동시에 인식한 뒤, 겹치는 구간의 중복 줄을 제거해 합칩니다.
+// 줄과 단어 좌표는 원본 이미지 기준으로 변환됩니다.
+func (s *OcrService) recognizeTiles(ctx context.Context, tiles []utils.ImageTile, pipeline *utils.ImagePipeline, options customTypes.OcrOptions) (*customTypes.OcrEngineResult, error) {
+	log.Printf("Preprocessing %d tiles for OCR: %v", len(tiles), pipeline.Stages())
+
+	results := make([]*customTypes.OcrEngineResult, len(tiles))
+	tileLines := make([][]customTypes.OcrLine, len(tiles))
+	errs := make([]error, len(tiles))
+	runParallel(len(tiles), s.tileConcurrency, func(i int) {
+		result, processed, err := s.recognizeProcessed(ctx, tiles[i].Image, pipeline, options)
+		if err != nil {
+			errs[i] = err
+			return
+		}
+		results[i] = result
+		tileLines[i] = utils.MapTileLines(tiles[i], processed, result.Lines)
+	})
+
+	stitched := &customTypes.OcrEngineResult{}
+	for i, result := range results {
+		if errs[i] != nil {
+			return nil, fmt.Errorf("failed to recognize tile %d (%v): %w", i, tiles[i].Rect, errs[i])
+		}
+		if stitched.Engine == "" {
+			stitched.Engine = result.Engine
+		}
+		stitched.Duration += result.Duration
+	}
+	stitched.Lines = utils.StitchTileLines(tiles, tileLines)
+	stitched.Text = utils.JoinLineTexts(stitched.Lines)
+	stitched.MeanConfidence = utils.MeanWordConfidence(stitched.Lines)
+	return stitched, nil
+}
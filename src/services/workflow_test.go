@@ -0,0 +1,188 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	customTypes "github.com/ndns-dev/ndns-tesseract/src/types"
+	"github.com/ndns-dev/ndns-tesseract/src/utils"
+)
+
+// flakyResultStore는 처음 failures 번의 Save를 PERSISTENCE_FAILED로 실패시키는 결과 저장소입니다.
+type flakyResultStore struct {
+	*MemoryResultStore
+	failures int
+}
+
+func (s *flakyResultStore) Save(ctx context.Context, result customTypes.OcrResult) error {
+	if s.failures > 0 {
+		s.failures--
+		return utils.Errorf(customTypes.ErrorCodePersistence, "save failed")
+	}
+	return s.MemoryResultStore.Save(ctx, result)
+}
+
+// workflowFixture는 이미지 서버, 분석 API, 순서 기반 OCR 엔진과 메모리 저장소로 구성한 워크플로우 테스트 환경입니다.
+type workflowFixture struct {
+	service   *OcrService
+	engine    *sequenceEngine
+	results   *MemoryResultStore
+	jobStates *MemoryJobStateStore
+	images    *httptest.Server
+
+	mu           sync.Mutex
+	analyzeCalls int
+	analyzeDelay time.Duration
+	closed       chan struct{} // 테스트가 끝나면 닫혀 지연 중인 분석 API 응답을 끝냅니다.
+}
+
+// newWorkflowFixture는 이미지 서버와 분석 API를 띄웁니다. 엔진은 인식할 때마다 texts를 순서대로 반환합니다.
+func newWorkflowFixture(t *testing.T, texts ...string) *workflowFixture {
+	t.Helper()
+	f := &workflowFixture{
+		engine:    &sequenceEngine{texts: texts},
+		results:   NewMemoryResultStore(),
+		jobStates: NewMemoryJobStateStore(JobStateConfig{LeaseDuration: time.Minute, MaxAttempts: 3}),
+		images:    newImageServer(t),
+		closed:    make(chan struct{}),
+	}
+
+	analyze := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		f.analyzeCalls++
+		delay := f.analyzeDelay
+		f.mu.Unlock()
+		select {
+		case <-time.After(delay):
+		case <-f.closed:
+		}
+	}))
+	t.Cleanup(analyze.Close)
+	t.Cleanup(func() { close(f.closed) })
+	t.Setenv("API_URL", analyze.URL)
+
+	f.service = NewOcrService(f.engine,
+		WithImageDownloader(noRetryDownloader()),
+		WithResultStore(f.results),
+		WithResultCache(nil),
+		WithJobStateStore(f.jobStates),
+		WithOcrVariants(nil),
+	)
+	return f
+}
+
+// url은 이미지 서버의 path URL을 반환합니다.
+func (f *workflowFixture) url(path string) string {
+	return f.images.URL + path
+}
+
+// analyzeCount는 분석 API 호출 횟수를 반환합니다.
+func (f *workflowFixture) analyzeCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.analyzeCalls
+}
+
+func TestHandleOcrWorkflowAllPositions(t *testing.T) {
+	f := newWorkflowFixture(t, "오늘 다녀온 카페", "맛있어요", "소정의 원고료를 받아 작성했습니다")
+	queueState := customTypes.OcrQueueState{
+		JobId: "job-1",
+		Mode:  customTypes.OcrJobModeAllPositions,
+		CrawlResult: &customTypes.CrawlResult{
+			Url:             "https://blog/1",
+			FirstImageUrl:   f.url("/ok/first"),
+			FirstStickerUrl: f.url("/ok/sticker"),
+			LastImageUrl:    f.url("/ok/last"),
+		},
+	}
+
+	summary, err := f.service.HandleOcrWorkflow(context.Background(), queueState)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.Position != customTypes.OcrPositionLastImage || !summary.Disclosure.Detected || len(summary.Positions) != 3 {
+		t.Fatalf("unexpected summary %+v", summary)
+	}
+
+	// 위치마다 결과가 자기 이미지 URL로 저장되고, 요약(Positions)은 저장하지 않습니다.
+	page, err := f.results.ListByJobId(context.Background(), "job-1", ResultQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Results) != 3 {
+		t.Fatalf("stored %d results, want 3", len(page.Results))
+	}
+	for _, result := range page.Results {
+		if result.ImageUrl != queueState.CrawlResult.GetImageUrlByPosition(result.Position) {
+			t.Errorf("result for %s stored under %s", result.Position, result.ImageUrl)
+		}
+		if len(result.Positions) != 0 {
+			t.Errorf("result for %s carries the job summary", result.Position)
+		}
+		if result.OcrText == "" {
+			t.Errorf("unexpected stored result %+v", result)
+		}
+	}
+	if f.analyzeCount() != 1 {
+		t.Errorf("analyze API called %d times, want 1", f.analyzeCount())
+	}
+}
+
+func TestHandleOcrWorkflowRetryAfterSaveFailure(t *testing.T) {
+	f := newWorkflowFixture(t, "소정의 원고료를 받아 작성했습니다")
+	f.service.results = &flakyResultStore{MemoryResultStore: f.results, failures: 1}
+	queueState := customTypes.OcrQueueState{
+		JobId:           "job-1",
+		CurrentPosition: customTypes.OcrPositionFirstImage,
+		CrawlResult:     &customTypes.CrawlResult{Url: "https://blog/1", FirstImageUrl: f.url("/ok/first")},
+	}
+
+	_, err := f.service.HandleOcrWorkflow(context.Background(), queueState)
+	wantCode(t, err, customTypes.ErrorCodePersistence)
+	if f.analyzeCount() != 0 {
+		t.Fatalf("analyze API called %d times before the result was saved", f.analyzeCount())
+	}
+
+	// 재시도는 저장에 성공한 뒤 분석 API를 한 번만 호출합니다.
+	if _, err := f.service.HandleOcrWorkflow(context.Background(), queueState); err != nil {
+		t.Fatal(err)
+	}
+	if f.analyzeCount() != 1 {
+		t.Errorf("analyze API called %d times across the retry, want 1", f.analyzeCount())
+	}
+	stored, err := f.results.GetByImageUrl(context.Background(), f.url("/ok/first"))
+	if err != nil || stored == nil {
+		t.Fatalf("result not stored after retry: %v", err)
+	}
+	state, err := f.jobStates.Get(context.Background(), "job-1", string(customTypes.OcrPositionFirstImage))
+	if err != nil || state == nil || state.Status != customTypes.JobStatusCompleted || state.Attempts != 2 {
+		t.Errorf("job state = %+v (%v), want COMPLETED after 2 attempts", state, err)
+	}
+}
+
+func TestHandleOcrWorkflowAnalyzeTimeout(t *testing.T) {
+	f := newWorkflowFixture(t, "소정의 원고료를 받아 작성했습니다")
+	f.analyzeDelay = time.Second
+	WithAnalyzeClient(&http.Client{Timeout: 20 * time.Millisecond})(f.service)
+	queueState := customTypes.OcrQueueState{
+		JobId:           "job-1",
+		CurrentPosition: customTypes.OcrPositionFirstImage,
+		CrawlResult:     &customTypes.CrawlResult{Url: "https://blog/1", FirstImageUrl: f.url("/ok/first")},
+	}
+
+	// 응답하지 않는 분석 API는 클라이언트 제한 시간에 끊기고, 다시 시도할 수 있는 실패로 기록됩니다.
+	start := time.Now()
+	_, err := f.service.HandleOcrWorkflow(context.Background(), queueState)
+	wantCode(t, err, customTypes.ErrorCodeAnalyzeApi)
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("HandleOcrWorkflow took %s, want it bounded by the analyze client timeout", elapsed)
+	}
+	state, err := f.jobStates.Get(context.Background(), "job-1", string(customTypes.OcrPositionFirstImage))
+	if err != nil || state == nil || state.Status != customTypes.JobStatusFailed || !state.Retryable {
+		t.Errorf("job state = %+v (%v), want a retryable FAILED state", state, err)
+	}
+}
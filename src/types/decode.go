@@ -0,0 +1,325 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FieldError는 요청 필드 하나의 검증 오류입니다. Field는 crawlResult.url, ocr.psm처럼 점으로 이은 경로입니다.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+func (e FieldError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+// FieldErrors는 요청 하나에서 발견된 필드 검증 오류 목록입니다.
+type FieldErrors []FieldError
+
+func (e FieldErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, fieldErr := range e {
+		messages[i] = fieldErr.Error()
+	}
+	return "invalid request: " + strings.Join(messages, "; ")
+}
+
+// add는 필드 오류를 추가합니다.
+func (e *FieldErrors) add(field, format string, args ...interface{}) {
+	*e = append(*e, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// addNested는 하위 검증 오류를 prefix 경로 아래에 추가합니다. FieldErrors가 아닌 오류는 prefix 필드의 오류로 추가합니다.
+func (e *FieldErrors) addNested(prefix string, err error) {
+	var nested FieldErrors
+	if !errors.As(err, &nested) {
+		e.add(prefix, "%v", err)
+		return
+	}
+	for _, fieldErr := range nested {
+		field := prefix
+		switch {
+		case fieldErr.Field == "":
+		case strings.HasPrefix(fieldErr.Field, "["):
+			field += fieldErr.Field
+		default:
+			field += "." + fieldErr.Field
+		}
+		*e = append(*e, FieldError{Field: field, Message: fieldErr.Message})
+	}
+}
+
+// crawlResultFormPrefix는 폼 요청에서 CrawlResult 필드를 나타내는 접두사입니다 (예: crawlResult.url).
+const crawlResultFormPrefix = "crawlResult."
+
+// queueStateObjectFields는 폼 값이나 SQS 메시지 속성으로 받을 때 JSON 문자열로 전달되는 필드입니다.
+var queueStateObjectFields = map[string]bool{
+	"crawlResult": true,
+	"preprocess":  true,
+	"ocr":         true,
+	"positionOcr": true,
+	"variants":    true,
+}
+
+// DecodeOcrQueueStateJSON은 JSON 본문을 OcrQueueState로 해석하고 검증합니다.
+// 본문이 JSON 객체가 아니면 일반 오류를, 필드 값이 잘못되면 FieldErrors를 반환합니다.
+func DecodeOcrQueueStateJSON(data []byte) (OcrQueueState, error) {
+	fields, err := jsonFields(data)
+	if err != nil {
+		return OcrQueueState{}, err
+	}
+	return decodeOcrQueueState(fields)
+}
+
+// DecodeOcrQueueStateForm은 application/x-www-form-urlencoded 값을 OcrQueueState로 해석하고 검증합니다.
+// CrawlResult는 crawlResult.url처럼 점으로 이은 필드나 crawlResult JSON 문자열로 받을 수 있고,
+// preprocess, ocr, positionOcr, variants는 JSON 문자열로 받습니다.
+func DecodeOcrQueueStateForm(values url.Values) (OcrQueueState, error) {
+	fields := make(map[string]json.RawMessage, len(values))
+	crawlResult := make(map[string]string)
+	for key, vals := range values {
+		if len(vals) == 0 {
+			continue
+		}
+		if name, ok := strings.CutPrefix(key, crawlResultFormPrefix); ok {
+			crawlResult[name] = vals[0]
+			continue
+		}
+		fields[key] = stringField(key, vals[0])
+	}
+	if _, ok := fields["crawlResult"]; !ok && len(crawlResult) > 0 {
+		encoded, err := json.Marshal(crawlResult)
+		if err != nil {
+			return OcrQueueState{}, err
+		}
+		fields["crawlResult"] = encoded
+	}
+	return decodeOcrQueueState(fields)
+}
+
+// DecodeOcrQueueStateSQS는 SQS 메시지 본문과 문자열 메시지 속성을 OcrQueueState로 해석하고 검증합니다.
+// 본문이 비어 있으면 메시지 속성만으로 상태를 만들고, 둘 다 있는 필드는 본문 값을 사용합니다.
+func DecodeOcrQueueStateSQS(body string, attributes map[string]string) (OcrQueueState, error) {
+	fields := make(map[string]json.RawMessage)
+	if strings.TrimSpace(body) != "" {
+		var err error
+		if fields, err = jsonFields([]byte(body)); err != nil {
+			return OcrQueueState{}, err
+		}
+	}
+	for key, value := range attributes {
+		if _, ok := fields[key]; !ok {
+			fields[key] = stringField(key, value)
+		}
+	}
+	return decodeOcrQueueState(fields)
+}
+
+// jsonFields는 JSON 객체 본문을 최상위 필드별 원본 값으로 나눕니다.
+func jsonFields(data []byte) (map[string]json.RawMessage, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("malformed JSON body: %w", err)
+	}
+	if fields == nil {
+		return nil, errors.New("malformed JSON body: expected an object")
+	}
+	return fields, nil
+}
+
+// stringField는 폼 값이나 메시지 속성 문자열을 JSON 값으로 바꿉니다.
+// 객체 필드는 문자열 자체를 JSON으로 보고, 나머지는 JSON 문자열로 감쌉니다.
+func stringField(key, value string) json.RawMessage {
+	if queueStateObjectFields[key] {
+		if strings.TrimSpace(value) == "" {
+			return json.RawMessage("null")
+		}
+		return json.RawMessage(value)
+	}
+	encoded, _ := json.Marshal(value)
+	return encoded
+}
+
+// decodeOcrQueueState는 필드별 JSON 값을 OcrQueueState로 해석한 뒤 Validate로 검증합니다.
+// 모든 필드의 오류를 모아 한 번에 반환합니다. 알 수 없는 필드는 무시합니다.
+func decodeOcrQueueState(fields map[string]json.RawMessage) (OcrQueueState, error) {
+	var state OcrQueueState
+	var errs FieldErrors
+
+	state.JobId = decodeStringField(fields, "jobId", &errs)
+	state.ReqId = decodeStringField(fields, "reqId", &errs)
+	state.CurrentPosition = OcrPosition(decodeStringField(fields, "currentPosition", &errs))
+	state.Mode = OcrJobMode(decodeStringField(fields, "mode", &errs))
+
+	if raw, ok := presentField(fields, "is2025OrLater"); ok {
+		var value interface{}
+		if err := json.Unmarshal(raw, &value); err != nil {
+			errs.add("is2025OrLater", "must be a boolean")
+		} else {
+			switch v := value.(type) {
+			case bool:
+				state.Is2025OrLater = v
+			case string:
+				if v != "" {
+					parsed, err := strconv.ParseBool(v)
+					if err != nil {
+						errs.add("is2025OrLater", "must be a boolean, got %q", v)
+					}
+					state.Is2025OrLater = parsed
+				}
+			default:
+				errs.add("is2025OrLater", "must be a boolean")
+			}
+		}
+	}
+
+	if value := decodeStringField(fields, "requestedAt", &errs); value != "" {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			errs.add("requestedAt", "must be an RFC3339 timestamp, got %q", value)
+		}
+		state.RequestedAt = parsed
+	}
+
+	decodeObjectField(fields, "crawlResult", &state.CrawlResult, &errs)
+	decodeObjectField(fields, "preprocess", &state.Preprocess, &errs)
+	decodeObjectField(fields, "ocr", &state.Ocr, &errs)
+	decodeObjectField(fields, "positionOcr", &state.PositionOcr, &errs)
+	decodeObjectField(fields, "variants", &state.Variants, &errs)
+
+	// 형식이 잘못된 필드가 있으면 값 검증 오류가 섞여 헷갈리지 않도록 여기서 반환합니다.
+	if len(errs) > 0 {
+		return state, errs
+	}
+	if err := state.Validate(); err != nil {
+		return state, err
+	}
+	return state, nil
+}
+
+// presentField는 값이 있고 null이 아닌 필드를 반환합니다.
+func presentField(fields map[string]json.RawMessage, key string) (json.RawMessage, bool) {
+	raw, ok := fields[key]
+	if !ok || len(raw) == 0 || string(bytes.TrimSpace(raw)) == "null" {
+		return nil, false
+	}
+	return raw, true
+}
+
+// decodeStringField는 문자열 필드를 해석합니다. 문자열이 아니면 오류를 기록하고 빈 문자열을 반환합니다.
+func decodeStringField(fields map[string]json.RawMessage, key string, errs *FieldErrors) string {
+	raw, ok := presentField(fields, key)
+	if !ok {
+		return ""
+	}
+	var value string
+	if err := json.Unmarshal(raw, &value); err != nil {
+		errs.add(key, "must be a string")
+		return ""
+	}
+	return strings.TrimSpace(value)
+}
+
+// decodeObjectField는 객체/배열 필드를 target에 해석합니다. 타입이 맞지 않는 하위 필드는 경로를 붙여 기록합니다.
+func decodeObjectField(fields map[string]json.RawMessage, key string, target interface{}, errs *FieldErrors) {
+	raw, ok := presentField(fields, key)
+	if !ok {
+		return
+	}
+	err := json.Unmarshal(raw, target)
+	if err == nil {
+		return
+	}
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		errs.add(key+"."+typeErr.Field, "must be %s, got %s", typeErr.Type.String(), typeErr.Value)
+		return
+	}
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		errs.add(key, "malformed JSON: %v", err)
+		return
+	}
+	errs.add(key, "%v", err)
+}
+
+// Validate는 OcrQueueState의 필수 필드와 값 범위를 검증해 FieldErrors로 반환합니다.
+// ALL_POSITIONS 모드에서는 currentPosition이 필요하지 않습니다.
+func (s OcrQueueState) Validate() error {
+	var errs FieldErrors
+
+	if s.JobId == "" {
+		errs.add("jobId", "is required")
+	}
+	switch s.Mode {
+	case "", OcrJobModeSingle:
+		if s.CurrentPosition == "" {
+			errs.add("currentPosition", "is required")
+		}
+	case OcrJobModeAllPositions:
+	default:
+		errs.add("mode", "must be one of %s, %s", OcrJobModeSingle, OcrJobModeAllPositions)
+	}
+	if s.CurrentPosition != "" && !s.CurrentPosition.IsValid() {
+		errs.add("currentPosition", "unknown position %q", s.CurrentPosition)
+	}
+	if s.CrawlResult == nil {
+		errs.add("crawlResult", "is required")
+	}
+
+	if s.Preprocess != nil {
+		if err := s.Preprocess.Validate(); err != nil {
+			errs.addNested("preprocess", err)
+		}
+	}
+	if s.Ocr != nil {
+		if err := s.Ocr.Validate(); err != nil {
+			errs.addNested("ocr", err)
+		}
+	}
+	positions := make([]string, 0, len(s.PositionOcr))
+	for position := range s.PositionOcr {
+		positions = append(positions, string(position))
+	}
+	sort.Strings(positions)
+	for _, key := range positions {
+		position := OcrPosition(key)
+		options := s.PositionOcr[position]
+		field := "positionOcr." + string(position)
+		if !position.IsValid() {
+			errs.add(field, "unknown position")
+			continue
+		}
+		if options == nil {
+			continue
+		}
+		if err := options.Validate(); err != nil {
+			errs.addNested(field, err)
+		}
+	}
+	if err := ValidateOcrVariants(s.Variants); err != nil {
+		errs.addNested("variants", err)
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// CrawlUrl은 CrawlResult가 없어도 안전하게 크롤링한 글의 URL을 반환합니다.
+func (s OcrQueueState) CrawlUrl() string {
+	if s.CrawlResult == nil {
+		return ""
+	}
+	return s.CrawlResult.Url
+}
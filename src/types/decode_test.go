@@ -0,0 +1,195 @@
+package types
+
+import (
+	"errors"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// fieldNames는 FieldErrors의 필드 경로 목록을 반환합니다. FieldErrors가 아니면 nil입니다.
+func fieldNames(err error) []string {
+	var fieldErrs FieldErrors
+	if !errors.As(err, &fieldErrs) {
+		return nil
+	}
+	names := make([]string, len(fieldErrs))
+	for i, fieldErr := range fieldErrs {
+		names[i] = fieldErr.Field
+	}
+	return names
+}
+
+func TestDecodeOcrQueueStateJSON(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantFields []string // 비어 있으면 성공, 아니면 FieldErrors의 필드 경로
+		wantErr    bool     // FieldErrors가 아닌 일반 오류
+		check      func(t *testing.T, state OcrQueueState)
+	}{
+		{
+			name: "valid single position",
+			body: `{"jobId":" job-1 ","currentPosition":"FirstImageUrl","is2025OrLater":true,
+				"requestedAt":"2025-03-01T09:00:00Z","crawlResult":{"url":"https://blog/1","firstImageUrl":"https://img/1"}}`,
+			check: func(t *testing.T, state OcrQueueState) {
+				if state.JobId != "job-1" || state.CurrentPosition != OcrPositionFirstImage || !state.Is2025OrLater {
+					t.Errorf("unexpected state %+v", state)
+				}
+				if !state.RequestedAt.Equal(time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)) {
+					t.Errorf("RequestedAt = %v", state.RequestedAt)
+				}
+				if state.CrawlUrl() != "https://blog/1" {
+					t.Errorf("CrawlUrl = %q", state.CrawlUrl())
+				}
+			},
+		},
+		{
+			name: "all positions without current position",
+			body: `{"jobId":"job-1","mode":"ALL_POSITIONS","crawlResult":{"url":"u"}}`,
+			check: func(t *testing.T, state OcrQueueState) {
+				if state.Mode != OcrJobModeAllPositions || state.CurrentPosition != "" {
+					t.Errorf("Mode = %q, CurrentPosition = %q", state.Mode, state.CurrentPosition)
+				}
+			},
+		},
+		{
+			name: "is2025OrLater as string",
+			body: `{"jobId":"job-1","currentPosition":"LastImageUrl","is2025OrLater":"true","crawlResult":{}}`,
+			check: func(t *testing.T, state OcrQueueState) {
+				if !state.Is2025OrLater {
+					t.Error("Is2025OrLater should be true")
+				}
+			},
+		},
+		{name: "not JSON", body: `jobId=1`, wantErr: true},
+		{name: "JSON array", body: `[]`, wantErr: true},
+		{name: "JSON null", body: `null`, wantErr: true},
+		{
+			name:       "missing required fields",
+			body:       `{}`,
+			wantFields: []string{"jobId", "currentPosition", "crawlResult"},
+		},
+		{
+			name:       "wrong types are reported together",
+			body:       `{"jobId":1,"is2025OrLater":"maybe","requestedAt":"yesterday","crawlResult":{"url":3}}`,
+			wantFields: []string{"jobId", "is2025OrLater", "requestedAt", "crawlResult.url"},
+		},
+		{
+			name:       "unknown mode and position",
+			body:       `{"jobId":"job-1","mode":"SOME","currentPosition":"Middle","crawlResult":{}}`,
+			wantFields: []string{"mode", "currentPosition"},
+		},
+		{
+			name: "invalid options",
+			body: `{"jobId":"job-1","currentPosition":"FirstImageUrl","crawlResult":{},
+				"preprocess":{"crop":"diagonal"},"ocr":{"languages":"jpn"},
+				"positionOcr":{"Nowhere":{},"LastImageUrl":{"psm":99}},"variants":[{"name":""}]}`,
+			wantFields: []string{"preprocess.crop", "ocr.languages", "positionOcr.LastImageUrl.psm", "positionOcr.Nowhere", "variants[0].name"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state, err := DecodeOcrQueueStateJSON([]byte(tt.body))
+			switch {
+			case tt.wantErr:
+				if err == nil || fieldNames(err) != nil {
+					t.Fatalf("want a non-field error, got %v", err)
+				}
+			case len(tt.wantFields) > 0:
+				if got := fieldNames(err); !reflect.DeepEqual(got, tt.wantFields) {
+					t.Fatalf("fields = %v, want %v (err: %v)", got, tt.wantFields, err)
+				}
+			default:
+				if err != nil {
+					t.Fatal(err)
+				}
+				if tt.check != nil {
+					tt.check(t, state)
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeOcrQueueStateForm(t *testing.T) {
+	values := url.Values{
+		"jobId":                       {"job-1"},
+		"currentPosition":             {"FirstStickerUrl"},
+		"is2025OrLater":               {"1"},
+		"crawlResult.url":             {"https://blog/1"},
+		"crawlResult.firstStickerUrl": {"https://img/s1"},
+		"ocr":                         {`{"psm":11}`},
+	}
+	state, err := DecodeOcrQueueStateForm(values)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !state.Is2025OrLater || state.CrawlResult.FirstStickerUrl != "https://img/s1" || state.Ocr.Psm != 11 {
+		t.Errorf("unexpected state %+v", state)
+	}
+
+	// crawlResult JSON 문자열이 있으면 점으로 이은 필드보다 우선합니다.
+	values.Set("crawlResult", `{"url":"https://blog/2"}`)
+	if state, err = DecodeOcrQueueStateForm(values); err != nil {
+		t.Fatal(err)
+	}
+	if state.CrawlUrl() != "https://blog/2" {
+		t.Errorf("CrawlUrl = %q", state.CrawlUrl())
+	}
+
+	values.Set("ocr", `{psm`)
+	if got := fieldNames(mustFail(DecodeOcrQueueStateForm(values))); !reflect.DeepEqual(got, []string{"ocr"}) {
+		t.Errorf("fields = %v, want [ocr]", got)
+	}
+}
+
+func TestDecodeOcrQueueStateSQS(t *testing.T) {
+	attributes := map[string]string{
+		"jobId":           "from-attribute",
+		"currentPosition": "LastImageUrl",
+		"crawlResult":     `{"url":"https://blog/1"}`,
+	}
+
+	tests := []struct {
+		name      string
+		body      string
+		wantJobId string
+		wantErr   bool
+	}{
+		{name: "attributes only", body: "", wantJobId: "from-attribute"},
+		{name: "body wins over attributes", body: `{"jobId":"from-body"}`, wantJobId: "from-body"},
+		{name: "malformed body", body: `{"jobId":`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state, err := DecodeOcrQueueStateSQS(tt.body, attributes)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if state.JobId != tt.wantJobId || state.CurrentPosition != OcrPositionLastImage {
+				t.Errorf("unexpected state %+v", state)
+			}
+		})
+	}
+}
+
+func TestFieldErrorsError(t *testing.T) {
+	err := FieldErrors{{Field: "jobId", Message: "is required"}, {Field: "ocr.psm", Message: "too large"}}
+	if got := err.Error(); got != "invalid request: jobId: is required; ocr.psm: too large" {
+		t.Errorf("Error() = %q", got)
+	}
+}
+
+func mustFail(_ OcrQueueState, err error) error {
+	return err
+}
@@ -0,0 +1,68 @@
+package types
+
+import "net/http"
+
+// ErrorCode는 API 응답과 로그에 노출되는 안정적인 오류 코드입니다.
+// 클라이언트가 분기 처리에 사용하므로 값을 바꾸지 않습니다.
+type ErrorCode string
+
+const (
+	ErrorCodeInvalidRequest     ErrorCode = "INVALID_REQUEST"        // 요청 본문을 해석할 수 없음
+	ErrorCodeNotFound           ErrorCode = "NOT_FOUND"              // 지원하지 않는 API 경로
+	ErrorCodeValidation         ErrorCode = "VALIDATION_FAILED"      // 필수 값 누락, 허용되지 않는 옵션
+	ErrorCodeImageNotFound      ErrorCode = "IMAGE_NOT_FOUND"        // 이미지 서버가 404/410 응답
+	ErrorCodeImageRejected      ErrorCode = "IMAGE_REJECTED"         // 이미지 서버가 그 밖의 4xx 응답
+	ErrorCodeImageTimeout       ErrorCode = "IMAGE_DOWNLOAD_TIMEOUT" // 이미지 다운로드 시간 초과
+	ErrorCodeImageUnavailable   ErrorCode = "IMAGE_DOWNLOAD_FAILED"  // 이미지 서버 5xx/429, 네트워크 오류
+	ErrorCodeImageTooLarge      ErrorCode = "IMAGE_TOO_LARGE"        // 이미지 크기 제한 초과
+	ErrorCodeUnsupportedImage   ErrorCode = "UNSUPPORTED_IMAGE"      // 이미지가 아니거나 지원하지 않는 형식
+	ErrorCodeImageDecode        ErrorCode = "IMAGE_DECODE_FAILED"    // 이미지 디코딩 실패
+	ErrorCodeOcrEngine          ErrorCode = "OCR_ENGINE_FAILED"      // OCR 엔진 실행 실패
+	ErrorCodeAnalyzeApi         ErrorCode = "ANALYZE_API_FAILED"     // 분석 API 호출 실패 (5xx/429, 네트워크)
+	ErrorCodeAnalyzeApiRejected ErrorCode = "ANALYZE_API_REJECTED"   // 분석 API가 요청을 거부 (429 외 4xx)
+	ErrorCodePersistence        ErrorCode = "PERSISTENCE_FAILED"     // DynamoDB 저장/조회 실패
+	ErrorCodeJobDuplicate       ErrorCode = "JOB_ALREADY_PROCESSED"  // 같은 작업/위치가 이미 완료되었거나 재시도 한도를 넘음
+	ErrorCodeJobInProgress      ErrorCode = "JOB_IN_PROGRESS"        // 다른 워커가 같은 작업/위치를 처리 중
+	ErrorCodeJobNotFound        ErrorCode = "JOB_NOT_FOUND"          // 조회한 작업/위치의 상태가 없음
+	ErrorCodeInternal           ErrorCode = "INTERNAL_ERROR"         // 분류되지 않은 오류
+)
+
+// errorCodeSpecs는 오류 코드별 재시도 여부와 HTTP 상태 코드입니다.
+var errorCodeSpecs = map[ErrorCode]struct {
+	retryable  bool
+	httpStatus int
+}{
+	ErrorCodeInvalidRequest:     {false, http.StatusBadRequest},
+	ErrorCodeNotFound:           {false, http.StatusNotFound},
+	ErrorCodeValidation:         {false, http.StatusBadRequest},
+	ErrorCodeImageNotFound:      {false, http.StatusUnprocessableEntity},
+	ErrorCodeImageRejected:      {false, http.StatusUnprocessableEntity},
+	ErrorCodeImageTimeout:       {true, http.StatusGatewayTimeout},
+	ErrorCodeImageUnavailable:   {true, http.StatusBadGateway},
+	ErrorCodeImageTooLarge:      {false, http.StatusRequestEntityTooLarge},
+	ErrorCodeUnsupportedImage:   {false, http.StatusUnsupportedMediaType},
+	ErrorCodeImageDecode:        {false, http.StatusUnprocessableEntity},
+	ErrorCodeOcrEngine:          {true, http.StatusInternalServerError},
+	ErrorCodeAnalyzeApi:         {true, http.StatusBadGateway},
+	ErrorCodeAnalyzeApiRejected: {false, http.StatusBadGateway},
+	ErrorCodePersistence:        {true, http.StatusServiceUnavailable},
+	ErrorCodeJobDuplicate:       {false, http.StatusConflict},
+	ErrorCodeJobInProgress:      {true, http.StatusConflict},
+	ErrorCodeJobNotFound:        {false, http.StatusNotFound},
+	ErrorCodeInternal:           {true, http.StatusInternalServerError},
+}
+
+// Retryable은 같은 요청을 다시 시도하면 성공할 수 있는 오류인지 반환합니다.
+// 알 수 없는 코드는 재시도 대상으로 봅니다.
+func (c ErrorCode) Retryable() bool {
+	spec, ok := errorCodeSpecs[c]
+	return !ok || spec.retryable
+}
+
+// HTTPStatus는 오류 코드에 대응하는 HTTP 상태 코드를 반환합니다.
+func (c ErrorCode) HTTPStatus() int {
+	if spec, ok := errorCodeSpecs[c]; ok {
+		return spec.httpStatus
+	}
+	return http.StatusInternalServerError
+}
@@ -1,6 +1,8 @@
 package types
 
-import "time"
+import (
+	"time"
+)
 
 // 이미지 크기 제한
 const (
@@ -10,10 +12,75 @@ const (
 	CROP_WIDTH          = 100
 	OPTIMAL_WIDTH       = 1000 // 최적의 이미지 너비
 	OPTIMAL_HEIGHT      = 500  // 최적의 이미지 높이
+
+	DEFAULT_TARGET_TEXT_HEIGHT = 32 // 확대 시 목표 글자 높이 (픽셀)
+
+	TILE_OVERLAP    = 120 // 타일 간 겹치는 픽셀 (가장 큰 글자 줄 높이보다 커야 함)
+	MAX_IMAGE_TILES = 12  // 이미지 하나에서 OCR 하는 최대 타일 수
 )
 
 // 크롤링 설정
 const (
-	CRAWL_MAX_RETRIES = 3
-	CRAWL_RETRY_DELAY = 500 * time.Millisecond
+	CRAWL_MAX_RETRIES      = 3
+	CRAWL_RETRY_DELAY      = 500 * time.Millisecond
+	IMAGE_DOWNLOAD_TIMEOUT = 10 * time.Second // 다운로드 시도 한 번의 제한 시간
+	IMAGE_MAX_RETRY_AFTER  = 5 * time.Second  // Retry-After 헤더로 기다리는 최대 시간
+	MAX_IMAGE_BYTES        = 20 << 20         // 20MB
+)
+
+// BinarizeMethod는 이진화 방식입니다.
+type BinarizeMethod string
+
+const (
+	BinarizeNone    BinarizeMethod = ""        // 이진화하지 않음
+	BinarizeOtsu    BinarizeMethod = "otsu"    // 전역 Otsu 임계값
+	BinarizeSauvola BinarizeMethod = "sauvola" // 지역 적응형 Sauvola 임계값
 )
+
+// CropMode는 OCR 전에 이미지의 어느 영역을 남길지 나타냅니다.
+type CropMode string
+
+const (
+	CropTile    CropMode = ""        // MAX_IMAGE_DIMENSION을 넘는 이미지를 겹치는 타일로 나눠 전체를 인식 (기본값)
+	CropOptimal CropMode = "optimal" // 비율에 따라 상단 또는 가운데만 남김
+	CropNone    CropMode = "none"    // 크롭하지 않음
+	CropTop     CropMode = "top"     // 상단 CROP_HEIGHT 픽셀만 사용
+	CropCenter  CropMode = "center"  // 좌우 CROP_WIDTH 픽셀씩 잘라 가운데만 사용
+	CropRegions CropMode = "regions" // 텍스트 영역 탐지로 찾은 영역만 인식 (찾지 못하면 타일 모드)
+)
+
+// PreprocessOptions는 OCR 전 이미지 향상 단계를 요청별로 켜고 끕니다.
+// 모든 값이 기본값이면 향상 단계 없이, MAX_IMAGE_DIMENSION을 넘는 이미지만 겹치는 타일로 나눠(CropTile) 인식합니다.
+type PreprocessOptions struct {
+	Crop             CropMode       `json:"crop,omitempty" dynamodbav:"crop,omitempty"`                         // 크롭 영역
+	Grayscale        bool           `json:"grayscale,omitempty" dynamodbav:"grayscale,omitempty"`               // 흑백 변환
+	Binarize         BinarizeMethod `json:"binarize,omitempty" dynamodbav:"binarize,omitempty"`                 // 이진화 방식
+	AutoInvert       bool           `json:"autoInvert,omitempty" dynamodbav:"autoInvert,omitempty"`             // 어두운 배경의 밝은 글자 반전
+	Upscale          bool           `json:"upscale,omitempty" dynamodbav:"upscale,omitempty"`                   // 작은 글자 확대
+	TargetTextHeight int            `json:"targetTextHeight,omitempty" dynamodbav:"targetTextHeight,omitempty"` // 확대 목표 글자 높이 (0이면 기본값)
+	Denoise          bool           `json:"denoise,omitempty" dynamodbav:"denoise,omitempty"`                   // 중간값 필터 잡음 제거
+	Deskew           bool           `json:"deskew,omitempty" dynamodbav:"deskew,omitempty"`                     // 기울기 보정
+}
+
+// Validate는 알 수 없는 크롭/이진화 방식이나 잘못된 확대 목표를 거부합니다.
+// 오류는 crop, binarize처럼 옵션 필드 이름을 경로로 가진 FieldErrors입니다.
+func (o PreprocessOptions) Validate() error {
+	var errs FieldErrors
+	switch o.Crop {
+	case CropTile, CropOptimal, CropNone, CropTop, CropCenter, CropRegions:
+	default:
+		errs.add("crop", "unsupported crop mode %q", o.Crop)
+	}
+	switch o.Binarize {
+	case BinarizeNone, BinarizeOtsu, BinarizeSauvola:
+	default:
+		errs.add("binarize", "unsupported binarize method %q", o.Binarize)
+	}
+	if o.TargetTextHeight < 0 || o.TargetTextHeight > 200 {
+		errs.add("targetTextHeight", "must be between 0 and 200, got %d", o.TargetTextHeight)
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
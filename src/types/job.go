@@ -0,0 +1,64 @@
+package types
+
+import "time"
+
+// JobPositionAll은 ALL_POSITIONS 모드 작업의 OcrQueueStatus 정렬 키입니다.
+const JobPositionAll = "ALL_POSITIONS"
+
+// OcrJobState는 OcrQueueStatus 테이블의 작업 상태 항목입니다.
+// jobId(파티션 키)와 position(정렬 키) 한 쌍이 한 번의 처리 단위이며,
+// 워커는 조건부 쓰기로 리스를 잡은 뒤에만 OCR을 실행합니다.
+type OcrJobState struct {
+	JobId          string    `json:"jobId" dynamodbav:"jobId"`                                   // 파티션 키
+	Position       string    `json:"position" dynamodbav:"position"`                             // 정렬 키 (OcrPosition 또는 JobPositionAll)
+	Status         JobStatus `json:"status" dynamodbav:"status"`                                 // 작업 상태
+	Attempts       int       `json:"attempts" dynamodbav:"attempts"`                             // 리스를 잡은 횟수
+	LeaseOwner     string    `json:"leaseOwner,omitempty" dynamodbav:"leaseOwner,omitempty"`     // 현재 리스를 가진 워커의 토큰
+	LeaseExpiresAt time.Time `json:"leaseExpiresAt" dynamodbav:"leaseExpiresAt,unixtime"`        // 리스 만료 시각 (지나면 다른 워커가 잡을 수 있음)
+	ErrorCode      ErrorCode `json:"errorCode,omitempty" dynamodbav:"errorCode,omitempty"`       // 마지막 실패의 오류 코드
+	ErrorMessage   string    `json:"errorMessage,omitempty" dynamodbav:"errorMessage,omitempty"` // 마지막 실패의 오류 메시지
+	Retryable      bool      `json:"retryable" dynamodbav:"retryable"`                           // 마지막 실패가 재시도 가능한지 여부
+	ImageUrl       string    `json:"imageUrl,omitempty" dynamodbav:"imageUrl,omitempty"`         // 처리한 이미지 URL (ALL_POSITIONS면 비어 있음)
+	StartedAt      time.Time `json:"startedAt" dynamodbav:"startedAt"`                           // 마지막으로 리스를 잡은 시각
+	CreatedAt      time.Time `json:"createdAt" dynamodbav:"createdAt"`                           // 처음 잡힌 시각
+	UpdatedAt      time.Time `json:"updatedAt" dynamodbav:"updatedAt"`                           // 마지막 상태 변경 시각
+	CompletedAt    time.Time `json:"completedAt" dynamodbav:"completedAt"`                       // 완료 시각
+}
+
+// JobPosition은 작업 상태 테이블의 정렬 키를 반환합니다.
+func (s OcrQueueState) JobPosition() string {
+	if s.Mode == OcrJobModeAllPositions {
+		return JobPositionAll
+	}
+	return string(s.CurrentPosition)
+}
+
+// JobStatusResponse는 작업 상태 조회 응답입니다.
+// Status는 위치별 상태를 합친 값으로, 처리 중 > 대기 > 실패 > 완료 순으로 우선합니다.
+type JobStatusResponse struct {
+	JobId     string              `json:"jobId"`
+	Status    JobStatus           `json:"status"`
+	Positions []JobPositionStatus `json:"positions"`
+}
+
+// JobPositionStatus는 작업 상태 조회 응답의 위치 하나입니다. OCR 결과는 완료된 위치에만 포함됩니다.
+type JobPositionStatus struct {
+	Position       string              `json:"position"`
+	Status         JobStatus           `json:"status"`
+	Attempts       int                 `json:"attempts"`
+	ImageUrl       string              `json:"imageUrl,omitempty"`
+	OcrText        string              `json:"ocrText,omitempty"`
+	MeanConfidence float64             `json:"meanConfidence,omitempty"`
+	Disclosure     *DisclosureVerdict  `json:"disclosure,omitempty"`
+	Positions      []OcrPositionResult `json:"positions,omitempty"` // ALL_POSITIONS 작업의 위치별 결과
+	CacheHit       bool                `json:"cacheHit,omitempty"`
+	ErrorCode      ErrorCode           `json:"errorCode,omitempty"`
+	ErrorMessage   string              `json:"errorMessage,omitempty"`
+	Retryable      bool                `json:"retryable,omitempty"`
+	CreatedAt      time.Time           `json:"createdAt"`
+	StartedAt      *time.Time          `json:"startedAt,omitempty"`
+	UpdatedAt      time.Time           `json:"updatedAt"`
+	CompletedAt    *time.Time          `json:"completedAt,omitempty"`
+	LeaseExpiresAt *time.Time          `json:"leaseExpiresAt,omitempty"` // 처리 중일 때 리스 만료 시각
+	DurationMs     int64               `json:"durationMs,omitempty"`     // 마지막 시도의 처리 시간 (완료/실패 시)
+}
@@ -1,13 +1,22 @@
 package types
 
-import "time"
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
 
+// JobStatus는 OcrQueueStatus 테이블에 기록되는 작업 상태입니다.
 type JobStatus string
 
 const (
-	JobStatusPending   JobStatus = "PENDING"
-	JobStatusCompleted JobStatus = "COMPLETED"
-	JobStatusFailed    JobStatus = "FAILED"
+	JobStatusPending    JobStatus = "PENDING"    // 접수됨, 아직 처리하지 않음
+	JobStatusProcessing JobStatus = "PROCESSING" // 워커가 리스를 잡고 처리 중
+	JobStatusCompleted  JobStatus = "COMPLETED"  // 처리 완료 (중복 전달은 건너뜀)
+	JobStatusFailed     JobStatus = "FAILED"     // 처리 실패 (재시도 가능하면 다시 잡을 수 있음)
 )
 
 type OcrRequest struct {
@@ -24,12 +33,320 @@ const (
 	OcrPositionLastSticker   OcrPosition = "LastStickerUrl"
 )
 
+// OcrPositionOrder는 전체 위치 처리 시 이미지를 확인하는 순서입니다.
+var OcrPositionOrder = []OcrPosition{
+	OcrPositionFirstImage,
+	OcrPositionFirstSticker,
+	OcrPositionSecondSticker,
+	OcrPositionLastImage,
+	OcrPositionLastSticker,
+}
+
+// IsValid는 정의된 OcrPosition 값인지 확인합니다.
+func (p OcrPosition) IsValid() bool {
+	for _, pos := range OcrPositionOrder {
+		if p == pos {
+			return true
+		}
+	}
+	return false
+}
+
+// OcrPipelineVersion은 전처리, 타일링, 인식 기본값 등 결과에 영향을 주는 파이프라인의 버전입니다.
+// 동작이 바뀌면 올립니다. 저장된 결과는 (PipelineVersion, EngineVersion) 순서로 같거나 높은 결과로만 교체되므로
+// 엔진 업그레이드만으로는 올릴 필요가 없고, 낮은 버전의 결과는 캐시로 사용하지 않습니다.
+// 버전이 없는(0) 결과는 버전 기록 이전에 저장된 결과입니다.
+const OcrPipelineVersion = 1
+
 // OcrResult는 DynamoDB에 저장될 Ocr 결과 아이템을 나타냅니다.
 type OcrResult struct {
-	ImageUrl    string      `json:"imageUrl" dynamodbav:"imageUrl"`       // 프라이머리 키
-	JobId       string      `json:"jobId" dynamodbav:"jobId"`             // State 키
-	Position    OcrPosition `json:"position" dynamodbav:"position"`       // Ocr 위치
-	OcrText     string      `json:"ocrText" dynamodbav:"ocrText"`         // Ocr 결과 텍스트
-	ProcessedAt time.Time   `json:"processedAt" dynamodbav:"processedAt"` // 처리 시간
-	Error       string      `json:"error" dynamodbav:"error"`             // 오류 메시지
+	ImageUrl        string              `json:"imageUrl" dynamodbav:"imageUrl"`                                // 프라이머리 키
+	JobId           string              `json:"jobId" dynamodbav:"jobId"`                                      // State 키
+	Position        OcrPosition         `json:"position" dynamodbav:"position"`                                // Ocr 위치
+	OcrText         string              `json:"ocrText" dynamodbav:"ocrText"`                                  // Ocr 결과 텍스트
+	Lines           []OcrLine           `json:"lines,omitempty" dynamodbav:"lines,omitempty"`                  // 줄/단어 단위 인식 결과
+	MeanConfidence  float64             `json:"meanConfidence" dynamodbav:"meanConfidence"`                    // 단어 평균 신뢰도 (0~100)
+	Disclosure      *DisclosureVerdict  `json:"disclosure,omitempty" dynamodbav:"disclosure,omitempty"`        // 협찬 문구 탐지 결과
+	Positions       []OcrPositionResult `json:"positions,omitempty" dynamodbav:"positions,omitempty"`          // 전체 위치 처리 시 위치별 결과
+	ContentHash     string              `json:"contentHash,omitempty" dynamodbav:"contentHash,omitempty"`      // 이미지 내용 SHA-256 해시
+	ProcessingKey   string              `json:"processingKey,omitempty" dynamodbav:"processingKey,omitempty"`  // 전처리/OCR 옵션 식별 키 (기본 설정이면 빈 값)
+	Variant         string              `json:"variant,omitempty" dynamodbav:"variant,omitempty"`              // 다중 패스 OCR에서 선택된 변형 이름
+	VariantScore    float64             `json:"variantScore,omitempty" dynamodbav:"variantScore,omitempty"`    // 선택된 변형의 점수 (0~1)
+	EngineVersion   string              `json:"engineVersion,omitempty" dynamodbav:"engineVersion,omitempty"`  // 인식에 사용한 OCR 엔진 버전
+	PipelineVersion int                 `json:"pipelineVersion" dynamodbav:"pipelineVersion"`                  // 인식 파이프라인 버전 (OcrPipelineVersion)
+	CacheHit        bool                `json:"cacheHit" dynamodbav:"-"`                                       // 캐시된 결과 사용 여부
+	ProcessedAt     time.Time           `json:"processedAt" dynamodbav:"processedAt"`                          // 처리 시간
+	ExpiresAt       *time.Time          `json:"expiresAt,omitempty" dynamodbav:"expiresAt,unixtime,omitempty"` // DynamoDB TTL 만료 시각 (epoch 초)
+	Error           string              `json:"error" dynamodbav:"error"`                                      // 오류 메시지
+}
+
+// OcrPositionResult는 전체 위치 처리 모드에서 위치 하나의 처리 결과를 요약합니다.
+type OcrPositionResult struct {
+	Position       OcrPosition        `json:"position" dynamodbav:"position"`
+	ImageUrl       string             `json:"imageUrl" dynamodbav:"imageUrl"`
+	OcrText        string             `json:"ocrText" dynamodbav:"ocrText"`
+	MeanConfidence float64            `json:"meanConfidence" dynamodbav:"meanConfidence"`
+	Disclosure     *DisclosureVerdict `json:"disclosure,omitempty" dynamodbav:"disclosure,omitempty"`
+	Error          string             `json:"error,omitempty" dynamodbav:"error,omitempty"`
+}
+
+// OcrJobResult는 ALL_POSITIONS 작업의 처리 결과입니다.
+// Summary는 판정에 사용된 위치의 결과에 처리한 모든 위치의 요약(Positions)을 붙인 것으로 호출자와 분석 API에 전달되고,
+// Results는 성공한 위치마다 하나씩 OcrResult 테이블에 이미지 URL로 저장되는 결과입니다.
+type OcrJobResult struct {
+	Summary OcrResult
+	Results []OcrResult
+}
+
+// DisclosureVerdict는 OCR 텍스트에서 협찬/광고 고지 문구를 탐지한 결과입니다.
+type DisclosureVerdict struct {
+	Detected      bool    `json:"detected" dynamodbav:"detected"`                               // 임계값 이상으로 일치하는 문구 존재 여부
+	MatchedPhrase string  `json:"matchedPhrase,omitempty" dynamodbav:"matchedPhrase,omitempty"` // 사전에서 가장 잘 일치한 문구
+	MatchedText   string  `json:"matchedText,omitempty" dynamodbav:"matchedText,omitempty"`     // OCR 텍스트에서 일치한 부분
+	Score         float64 `json:"score" dynamodbav:"score"`                                     // 일치 점수 (0~1)
+}
+
+// OcrOptions는 OCR 엔진에 전달되는 인식 옵션입니다.
+type OcrOptions struct {
+	Languages    string            `json:"languages,omitempty" dynamodbav:"languages,omitempty"`       // 인식 언어 (예: kor, kor+eng)
+	Psm          int               `json:"psm,omitempty" dynamodbav:"psm,omitempty"`                   // 페이지 분할 모드 (0이면 기본값)
+	Oem          int               `json:"oem,omitempty" dynamodbav:"oem,omitempty"`                   // OCR 엔진 모드 (0이면 기본값)
+	Variables    map[string]string `json:"variables,omitempty" dynamodbav:"variables,omitempty"`       // -c 로 전달되는 설정 변수
+	UserWords    string            `json:"userWords,omitempty" dynamodbav:"userWords,omitempty"`       // 사용자 단어 파일 이름 (사용자 파일 디렉토리 기준)
+	UserPatterns string            `json:"userPatterns,omitempty" dynamodbav:"userPatterns,omitempty"` // 사용자 패턴 파일 이름 (사용자 파일 디렉토리 기준)
+}
+
+// DefaultOcrOptions는 기존 Tesseract 호출과 동일한 기본 옵션을 반환합니다.
+func DefaultOcrOptions() OcrOptions {
+	return OcrOptions{
+		Languages: "kor",
+		Psm:       6,
+		Oem:       3,
+		Variables: map[string]string{
+			"preserve_interword_spaces": "1",
+		},
+	}
+}
+
+// 요청으로 지정할 수 있는 OCR 옵션 허용 목록
+var (
+	// AllowedOcrLanguages는 Lambda 이미지에 포함된 언어 데이터입니다.
+	AllowedOcrLanguages = []string{"kor", "kor_vert", "eng", "osd"}
+
+	// AllowedOcrVariables는 -c 로 전달할 수 있는 Tesseract 설정 변수입니다.
+	AllowedOcrVariables = []string{
+		"preserve_interword_spaces",
+		"tessedit_char_whitelist",
+		"tessedit_char_blacklist",
+		"tessedit_do_invert",
+		"load_system_dawg",
+		"load_freq_dawg",
+		"user_defined_dpi",
+		"textord_min_linesize",
+		"textord_heavy_nr",
+		"classify_bln_numeric_mode",
+	}
+)
+
+const maxOcrVariableValueLength = 256
+
+// userFileNamePattern은 사용자 단어/패턴 파일 이름으로 허용되는 형식입니다. 경로 구분자는 허용하지 않습니다.
+var userFileNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+
+// Validate는 허용 목록에 없는 언어나 설정 변수, 범위를 벗어난 PSM/OEM, 잘못된 파일 이름을 거부합니다.
+// 오류는 psm, variables.<이름>처럼 옵션 필드 이름을 경로로 가진 FieldErrors입니다.
+func (o OcrOptions) Validate() error {
+	var errs FieldErrors
+	if o.Languages != "" {
+		for _, lang := range strings.Split(o.Languages, "+") {
+			if !slices.Contains(AllowedOcrLanguages, lang) {
+				errs.add("languages", "unsupported OCR language %q", lang)
+			}
+		}
+	}
+	// PSM 0은 방향 감지만 수행해 텍스트가 나오지 않으므로 기본값 의미로만 사용합니다.
+	// PSM 2는 페이지 분할만 하고 OCR을 수행하지 않으므로 허용하지 않습니다.
+	if o.Psm < 0 || o.Psm > 13 || o.Psm == 2 {
+		errs.add("psm", "must be 0 (default), 1 or 3..13, got %d", o.Psm)
+	}
+	// OEM 0(레거시 엔진)은 기본값 의미로만 사용합니다. 레거시 엔진을 쓰는 OEM 0, 2는
+	// LSTM 전용 언어 데이터에서 동작하지 않으므로 LSTM(1)과 엔진 기본값(3)만 허용합니다.
+	if o.Oem != 0 && o.Oem != 1 && o.Oem != 3 {
+		errs.add("oem", "must be 0 (default), 1 or 3, got %d", o.Oem)
+	}
+	keys := make([]string, 0, len(o.Variables))
+	for key := range o.Variables {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value := o.Variables[key]
+		if !slices.Contains(AllowedOcrVariables, key) {
+			errs.add("variables."+key, "unsupported OCR variable")
+		} else if len(value) > maxOcrVariableValueLength || strings.ContainsAny(value, "\r\n\x00") {
+			errs.add("variables."+key, "invalid value")
+		}
+	}
+	for _, file := range []struct{ field, name string }{{"userWords", o.UserWords}, {"userPatterns", o.UserPatterns}} {
+		if file.name != "" && (!userFileNamePattern.MatchString(file.name) || strings.Contains(file.name, "..")) {
+			errs.add(file.field, "invalid user file name %q", file.name)
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// Merge는 override에 지정된 값으로 덮어쓴 옵션을 반환합니다.
+// 0이나 빈 값은 덮어쓰지 않으며, Variables는 키 단위로 합쳐집니다.
+func (o OcrOptions) Merge(override *OcrOptions) OcrOptions {
+	if override == nil {
+		return o
+	}
+
+	merged := o
+	if override.Languages != "" {
+		merged.Languages = override.Languages
+	}
+	if override.Psm != 0 {
+		merged.Psm = override.Psm
+	}
+	if override.Oem != 0 {
+		merged.Oem = override.Oem
+	}
+	if override.UserWords != "" {
+		merged.UserWords = override.UserWords
+	}
+	if override.UserPatterns != "" {
+		merged.UserPatterns = override.UserPatterns
+	}
+	if len(override.Variables) > 0 {
+		merged.Variables = make(map[string]string, len(o.Variables)+len(override.Variables))
+		for k, v := range o.Variables {
+			merged.Variables[k] = v
+		}
+		for k, v := range override.Variables {
+			merged.Variables[k] = v
+		}
+	}
+	return merged
+}
+
+// OcrVariant는 다중 패스 OCR에서 시도할 인식 설정 하나입니다.
+type OcrVariant struct {
+	Name       string             `json:"name" dynamodbav:"name"`                                 // 결과에 기록되는 변형 이름
+	Ocr        *OcrOptions        `json:"ocr,omitempty" dynamodbav:"ocr,omitempty"`               // 요청 OCR 옵션 위에 덮어쓸 옵션
+	Preprocess *PreprocessOptions `json:"preprocess,omitempty" dynamodbav:"preprocess,omitempty"` // 지정하면 요청 전처리 옵션 대신 사용
+}
+
+// MaxOcrVariants는 한 번에 시도할 수 있는 최대 변형 수입니다.
+const MaxOcrVariants = 6
+
+// DefaultOcrVariants는 다중 패스 OCR을 켰을 때 시도하는 기본 변형입니다.
+// 스티커처럼 짧은 글자는 단일 줄(PSM 7)이나 흩어진 글자(PSM 11) 모드, 이진화 이미지에서 더 잘 읽히는 경우가 많습니다.
+func DefaultOcrVariants() []OcrVariant {
+	return []OcrVariant{
+		{Name: "default"},
+		{
+			Name:       "single-line-binarized",
+			Ocr:        &OcrOptions{Psm: 7},
+			Preprocess: &PreprocessOptions{Grayscale: true, AutoInvert: true, Upscale: true, Binarize: BinarizeSauvola},
+		},
+		{
+			Name:       "sparse-text",
+			Ocr:        &OcrOptions{Psm: 11},
+			Preprocess: &PreprocessOptions{Grayscale: true, AutoInvert: true, Upscale: true},
+		},
+		{
+			Name:       "text-regions",
+			Preprocess: &PreprocessOptions{Crop: CropRegions, Grayscale: true, AutoInvert: true, Upscale: true},
+		},
+		{
+			Name:       "center-otsu",
+			Preprocess: &PreprocessOptions{Crop: CropCenter, Grayscale: true, Binarize: BinarizeOtsu},
+		},
+	}
+}
+
+// ValidateOcrVariants는 변형 수, 이름 중복, 각 변형의 옵션을 검증합니다.
+// 오류는 [0].ocr.psm처럼 변형 위치를 경로로 가진 FieldErrors이며, 변형 수 오류는 경로가 비어 있습니다.
+func ValidateOcrVariants(variants []OcrVariant) error {
+	var errs FieldErrors
+	if len(variants) > MaxOcrVariants {
+		errs.add("", "too many OCR variants: %d (max %d)", len(variants), MaxOcrVariants)
+	}
+	names := make(map[string]bool, len(variants))
+	for i, variant := range variants {
+		field := fmt.Sprintf("[%d]", i)
+		if variant.Name == "" {
+			errs.add(field+".name", "is required")
+		} else if names[variant.Name] {
+			errs.add(field+".name", "duplicate OCR variant name %q", variant.Name)
+		}
+		names[variant.Name] = true
+		if variant.Ocr != nil {
+			if err := variant.Ocr.Validate(); err != nil {
+				errs.addNested(field+".ocr", err)
+			}
+		}
+		if variant.Preprocess != nil {
+			if err := variant.Preprocess.Validate(); err != nil {
+				errs.addNested(field+".preprocess", err)
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// BoundingBox는 이미지 내 영역을 픽셀 단위로 나타냅니다.
+type BoundingBox struct {
+	Left   int `json:"left" dynamodbav:"left"`
+	Top    int `json:"top" dynamodbav:"top"`
+	Width  int `json:"width" dynamodbav:"width"`
+	Height int `json:"height" dynamodbav:"height"`
+}
+
+// Union은 두 영역을 모두 포함하는 최소 영역을 반환합니다.
+func (b BoundingBox) Union(other BoundingBox) BoundingBox {
+	if b.Width == 0 && b.Height == 0 {
+		return other
+	}
+	if other.Width == 0 && other.Height == 0 {
+		return b
+	}
+	left := min(b.Left, other.Left)
+	top := min(b.Top, other.Top)
+	right := max(b.Left+b.Width, other.Left+other.Width)
+	bottom := max(b.Top+b.Height, other.Top+other.Height)
+	return BoundingBox{Left: left, Top: top, Width: right - left, Height: bottom - top}
+}
+
+// OcrWord는 단어 단위 인식 결과입니다.
+type OcrWord struct {
+	Text        string      `json:"text" dynamodbav:"text"`
+	Confidence  float64     `json:"confidence" dynamodbav:"confidence"` // 0~100
+	BoundingBox BoundingBox `json:"boundingBox" dynamodbav:"boundingBox"`
+}
+
+// OcrLine은 줄 단위 인식 결과입니다.
+type OcrLine struct {
+	Text        string      `json:"text" dynamodbav:"text"`
+	Confidence  float64     `json:"confidence" dynamodbav:"confidence"` // 줄 내 단어 평균 신뢰도
+	BoundingBox BoundingBox `json:"boundingBox" dynamodbav:"boundingBox"`
+	Words       []OcrWord   `json:"words" dynamodbav:"words"`
+}
+
+// OcrEngineResult는 OCR 엔진의 인식 결과를 나타냅니다.
+type OcrEngineResult struct {
+	Engine         string        `json:"engine"`         // 사용된 엔진 이름
+	Text           string        `json:"text"`           // 인식된 텍스트
+	Lines          []OcrLine     `json:"lines"`          // 줄/단어 단위 결과
+	MeanConfidence float64       `json:"meanConfidence"` // 단어 평균 신뢰도
+	Duration       time.Duration `json:"duration"`       // 인식 소요 시간
+	Variant        string        `json:"variant"`        // 다중 패스 OCR에서 선택된 변형 이름
+	Score          float64       `json:"score"`          // 다중 패스 OCR 선택 점수 (0~1)
 }
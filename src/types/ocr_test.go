@@ -0,0 +1,120 @@
+package types
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestOcrOptionsValidate(t *testing.T) {
+	tests := []struct {
+		name       string
+		options    OcrOptions
+		wantFields []string
+	}{
+		{name: "defaults", options: DefaultOcrOptions()},
+		{name: "zero value", options: OcrOptions{}},
+		{name: "unsupported language", options: OcrOptions{Languages: "kor+jpn"}, wantFields: []string{"languages"}},
+		{name: "psm out of range", options: OcrOptions{Psm: 14}, wantFields: []string{"psm"}},
+		{name: "page segmentation only psm", options: OcrOptions{Psm: 2}, wantFields: []string{"psm"}},
+		{name: "single line psm", options: OcrOptions{Psm: 7}},
+		{name: "oem out of range", options: OcrOptions{Oem: -1}, wantFields: []string{"oem"}},
+		{name: "legacy oem", options: OcrOptions{Oem: 2}, wantFields: []string{"oem"}},
+		{name: "lstm oem", options: OcrOptions{Psm: 13, Oem: 1}},
+		{name: "default engine oem", options: OcrOptions{Psm: 1, Oem: 3}},
+		{
+			name:       "variables",
+			options:    OcrOptions{Variables: map[string]string{"tessedit_char_whitelist": "a\nb", "debug_file": "/tmp/x"}},
+			wantFields: []string{"variables.debug_file", "variables.tessedit_char_whitelist"},
+		},
+		{
+			name:       "user files",
+			options:    OcrOptions{UserWords: "../words", UserPatterns: "a/b"},
+			wantFields: []string{"userWords", "userPatterns"},
+		},
+		{
+			name:       "every error is reported",
+			options:    OcrOptions{Languages: "xx", Psm: 99, Oem: 9},
+			wantFields: []string{"languages", "psm", "oem"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.options.Validate()
+			if len(tt.wantFields) == 0 {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			if got := fieldNames(err); !reflect.DeepEqual(got, tt.wantFields) {
+				t.Fatalf("fields = %v, want %v (err: %v)", got, tt.wantFields, err)
+			}
+		})
+	}
+}
+
+func TestPreprocessOptionsValidate(t *testing.T) {
+	err := PreprocessOptions{Crop: "diagonal", Binarize: "magic", TargetTextHeight: 500}.Validate()
+	want := []string{"crop", "binarize", "targetTextHeight"}
+	if got := fieldNames(err); !reflect.DeepEqual(got, want) {
+		t.Fatalf("fields = %v, want %v (err: %v)", got, want, err)
+	}
+	if err := (PreprocessOptions{}).Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestValidateOcrVariants(t *testing.T) {
+	tests := []struct {
+		name       string
+		variants   []OcrVariant
+		wantFields []string
+	}{
+		{name: "defaults", variants: DefaultOcrVariants()},
+		{name: "none", variants: nil},
+		{
+			name:       "names",
+			variants:   []OcrVariant{{Name: "a"}, {Name: ""}, {Name: "a"}},
+			wantFields: []string{"[1].name", "[2].name"},
+		},
+		{
+			name: "nested options",
+			variants: []OcrVariant{
+				{Name: "sparse", Ocr: &OcrOptions{Psm: 20}},
+				{Name: "binary", Preprocess: &PreprocessOptions{Crop: CropNone, Binarize: "magic"}},
+			},
+			wantFields: []string{"[0].ocr.psm", "[1].preprocess.binarize"},
+		},
+		{
+			name:       "too many",
+			variants:   namedVariants(MaxOcrVariants + 1),
+			wantFields: []string{""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateOcrVariants(tt.variants)
+			if len(tt.wantFields) == 0 {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			if got := fieldNames(err); !reflect.DeepEqual(got, tt.wantFields) {
+				t.Fatalf("fields = %v, want %v (err: %v)", got, tt.wantFields, err)
+			}
+		})
+	}
+}
+
+// namedVariants는 이름만 다른 변형 n개를 만듭니다.
+func namedVariants(n int) []OcrVariant {
+	variants := make([]OcrVariant, n)
+	for i := range variants {
+		variants[i].Name = fmt.Sprintf("variant-%d", i)
+	}
+	return variants
+}
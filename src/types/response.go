@@ -1,11 +1,33 @@
 package types
 
+import "time"
+
+// ANALYZE_API_TIMEOUT는 분석 API 호출 한 번의 기본 제한 시간입니다.
+const ANALYZE_API_TIMEOUT = 10 * time.Second
+
+// ApiResponse는 모든 HTTP 응답 본문의 공통 구조입니다.
+type ApiResponse struct {
+	Success bool           `json:"success"`
+	Data    interface{}    `json:"data,omitempty"`
+	Error   *ErrorResponse `json:"error,omitempty"`
+	Meta    ResponseMeta   `json:"meta"`
+}
+
+// ResponseMeta는 요청 추적과 처리 시간 정보입니다.
+type ResponseMeta struct {
+	RequestId  string    `json:"requestId"`
+	ReceivedAt time.Time `json:"receivedAt"`
+	DurationMs int64     `json:"durationMs"`
+}
+
 // ErrorResponse는 람다 함수의 에러 응답 구조체입니다.
 type ErrorResponse struct {
-	Message   string `json:"message"`
-	JobId     string `json:"JobId,omitempty"`
-	ImageURL  string `json:"imageUrl,omitempty"`
-	ErrorCode string `json:"errorCode"`
+	Message   string       `json:"message"`
+	JobId     string       `json:"JobId,omitempty"`
+	ImageURL  string       `json:"imageUrl,omitempty"`
+	ErrorCode ErrorCode    `json:"errorCode"`
+	Retryable bool         `json:"retryable"`
+	Fields    []FieldError `json:"fields,omitempty"` // 필드별 검증 오류 (VALIDATION_FAILED일 때)
 }
 
 type AnalyzeCycleParam struct {
@@ -2,14 +2,27 @@ package types
 
 import "time"
 
+// OcrJobMode는 작업 처리 방식을 나타냅니다.
+type OcrJobMode string
+
+const (
+	OcrJobModeSingle       OcrJobMode = "SINGLE"        // CurrentPosition 한 곳만 처리 (기본값)
+	OcrJobModeAllPositions OcrJobMode = "ALL_POSITIONS" // CrawlResult의 모든 이미지 위치를 순서대로 처리
+)
+
 // OcrQueueState는 Ocr 처리 상태를 관리합니다
 type OcrQueueState struct {
-	JobId           string       `json:"jobId" dynamodbav:"jobId"`                                 // 작업 ID
-	ReqId           string       `json:"reqId" dynamodbav:"reqId"`                                 // 요청 ID (SSE 매핑용)
-	CurrentPosition OcrPosition  `json:"currentPosition" dynamodbav:"currentPosition"`             // 현재 OCR 위치
-	Is2025OrLater   bool         `json:"is2025OrLater" dynamodbav:"is2025OrLater"`                 // 2025년 이후 포스트 여부
-	CrawlResult     *CrawlResult `json:"crawlResult,omitempty" dynamodbav:"crawlResult,omitempty"` // 크롤링 결과
-	RequestedAt     time.Time    `json:"requestedAt" dynamodbav:"requestedAt"`                     // 요청 시간
+	JobId           string                      `json:"jobId" dynamodbav:"jobId"`                                 // 작업 ID
+	ReqId           string                      `json:"reqId" dynamodbav:"reqId"`                                 // 요청 ID (SSE 매핑용)
+	CurrentPosition OcrPosition                 `json:"currentPosition" dynamodbav:"currentPosition"`             // 현재 OCR 위치
+	Mode            OcrJobMode                  `json:"mode,omitempty" dynamodbav:"mode,omitempty"`               // 작업 처리 방식
+	Is2025OrLater   bool                        `json:"is2025OrLater" dynamodbav:"is2025OrLater"`                 // 2025년 이후 포스트 여부
+	CrawlResult     *CrawlResult                `json:"crawlResult,omitempty" dynamodbav:"crawlResult,omitempty"` // 크롤링 결과
+	Preprocess      *PreprocessOptions          `json:"preprocess,omitempty" dynamodbav:"preprocess,omitempty"`   // 이미지 전처리 옵션
+	Ocr             *OcrOptions                 `json:"ocr,omitempty" dynamodbav:"ocr,omitempty"`                 // OCR 엔진 옵션
+	PositionOcr     map[OcrPosition]*OcrOptions `json:"positionOcr,omitempty" dynamodbav:"positionOcr,omitempty"` // 위치별 OCR 옵션 (ocr 위에 덮어씀)
+	Variants        []OcrVariant                `json:"variants,omitempty" dynamodbav:"variants,omitempty"`       // 다중 패스 OCR 변형 (비어 있으면 서비스 설정)
+	RequestedAt     time.Time                   `json:"requestedAt" dynamodbav:"requestedAt"`                     // 요청 시간
 }
 
 type CrawlResult struct {
@@ -45,6 +58,9 @@ func (c *CrawlResult) GetImageUrlByPosition(position OcrPosition) string {
 type TableName string
 
 const (
-	OcrResultTableName      TableName = "OcrResult"
-	OcrQueueStatusTableName TableName = "OcrQueueStatus"
+	OcrResultTableName      TableName = "OcrResult"      // imageUrl 파티션 키
+	OcrQueueStatusTableName TableName = "OcrQueueStatus" // jobId 파티션 키, position 정렬 키
 )
+
+// OcrResultJobIdIndexName은 OcrResult 테이블을 jobId(파티션 키), position(정렬 키)으로 조회하는 GSI입니다.
+const OcrResultJobIdIndexName = "JobIdIndex"
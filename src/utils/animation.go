@@ -0,0 +1,449 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	"math"
+	"math/bits"
+
+	types "github.com/ndns-dev/ndns-tesseract/src/types"
+	"golang.org/x/image/webp"
+)
+
+// DefaultMaxAnimationFrames는 애니메이션에서 OCR 할 최대 프레임 수입니다.
+const DefaultMaxAnimationFrames = 3
+
+// 합성한 프레임은 모두 전체 크기 RGBA 복사본으로 메모리에 올라가므로 프레임 수와 전체 픽셀 수를 제한합니다.
+const (
+	MaxAnimationFrames = 120        // 디코딩하는 최대 프레임 수
+	MaxAnimationPixels = 40_000_000 // 프레임 수 x 캔버스 크기의 상한 (RGBA 기준 약 160MB)
+)
+
+// frameHashDistanceThreshold 이하의 해시 거리를 가진 프레임은 같은 프레임으로 간주합니다.
+const frameHashDistanceThreshold = 4
+
+// IsAnimatedImageType은 애니메이션을 포함할 수 있는 MIME 타입인지 확인합니다.
+func IsAnimatedImageType(contentType string) bool {
+	return contentType == "image/gif" || contentType == "image/webp"
+}
+
+// DecodeAnimationFrames는 GIF/WebP 이미지를 합성이 끝난 전체 크기 프레임 목록으로 디코딩합니다.
+// 투명 영역은 흰색 배경으로 채웁니다. 정적 이미지는 프레임 하나를 반환합니다.
+func DecodeAnimationFrames(data []byte, contentType string) ([]image.Image, error) {
+	switch contentType {
+	case "image/gif":
+		return decodeGifFrames(data)
+	case "image/webp":
+		return decodeWebpFrames(data)
+	default:
+		img, _, err := image.Decode(bytes.NewReader(data))
+		if err != nil {
+			return nil, Errorf(types.ErrorCodeImageDecode, "이미지 디코딩 실패: %v", err)
+		}
+		return []image.Image{img}, nil
+	}
+}
+
+// decodeGifFrames는 GIF의 disposal 방식을 반영해 각 프레임을 합성합니다.
+func decodeGifFrames(data []byte) ([]image.Image, error) {
+	// gif.DecodeAll은 모든 프레임의 픽셀을 한 번에 메모리에 올리므로, 헤더의 캔버스 크기와
+	// 블록 구조에서 센 프레임 수로 먼저 예산을 확인합니다.
+	config, err := gif.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, Errorf(types.ErrorCodeImageDecode, "GIF 디코딩 실패: %v", err)
+	}
+	frameCount, err := countGifFrames(data)
+	if err != nil {
+		return nil, Errorf(types.ErrorCodeImageDecode, "GIF 디코딩 실패: %v", err)
+	}
+	if err := checkAnimationBudget(frameCount, image.Rect(0, 0, config.Width, config.Height)); err != nil {
+		return nil, err
+	}
+
+	g, err := gif.DecodeAll(bytes.NewReader(data))
+	if err != nil {
+		return nil, Errorf(types.ErrorCodeImageDecode, "GIF 디코딩 실패: %v", err)
+	}
+
+	bounds := image.Rect(0, 0, g.Config.Width, g.Config.Height)
+	if bounds.Empty() && len(g.Image) > 0 {
+		bounds = g.Image[0].Bounds()
+	}
+	if err := checkAnimationBudget(len(g.Image), bounds); err != nil {
+		return nil, err
+	}
+	canvas := image.NewRGBA(bounds)
+	frames := make([]image.Image, 0, len(g.Image))
+
+	for i, frame := range g.Image {
+		var previous *image.RGBA
+		disposal := byte(gif.DisposalNone)
+		if i < len(g.Disposal) {
+			disposal = g.Disposal[i]
+		}
+		if disposal == gif.DisposalPrevious {
+			previous = cloneRGBA(canvas)
+		}
+
+		draw.Draw(canvas, frame.Bounds(), frame, frame.Bounds().Min, draw.Over)
+		frames = append(frames, flattenOnWhite(canvas))
+
+		switch disposal {
+		case gif.DisposalBackground:
+			draw.Draw(canvas, frame.Bounds(), image.Transparent, image.Point{}, draw.Src)
+		case gif.DisposalPrevious:
+			canvas = previous
+		}
+	}
+	return frames, nil
+}
+
+// GIF 블록 식별자
+const (
+	gifBlockImage     = 0x2C
+	gifBlockExtension = 0x21
+	gifBlockTrailer   = 0x3B
+)
+
+// countGifFrames는 LZW 데이터를 풀지 않고 GIF 블록 구조만 따라가며 이미지 프레임 수를 셉니다.
+// 프레임 수가 MaxAnimationFrames를 넘으면 나머지는 확인하지 않고 MaxAnimationFrames+1을 반환합니다.
+func countGifFrames(data []byte) (int, error) {
+	if len(data) < 13 {
+		return 0, fmt.Errorf("GIF 헤더 형식 오류")
+	}
+	pos := 13
+	if flags := data[10]; flags&0x80 != 0 {
+		pos += 3 << (flags&0x07 + 1) // 전역 색상표
+	}
+
+	frames := 0
+	for pos < len(data) {
+		switch data[pos] {
+		case gifBlockTrailer:
+			return frames, nil
+		case gifBlockExtension:
+			pos += 2 // 블록 식별자, 확장 레이블
+		case gifBlockImage:
+			frames++
+			if frames > MaxAnimationFrames {
+				return frames, nil
+			}
+			if pos+10 > len(data) {
+				return 0, fmt.Errorf("GIF 이미지 블록 길이 오류")
+			}
+			flags := data[pos+9]
+			pos += 10
+			if flags&0x80 != 0 {
+				pos += 3 << (flags&0x07 + 1) // 지역 색상표
+			}
+			pos++ // LZW 최소 코드 크기
+		default:
+			return 0, fmt.Errorf("GIF 블록 식별자 오류: 0x%02x", data[pos])
+		}
+
+		// 데이터 서브 블록은 길이 바이트로 시작하고 길이 0인 블록으로 끝납니다.
+		for {
+			if pos >= len(data) {
+				return 0, fmt.Errorf("GIF 서브 블록 길이 오류")
+			}
+			size := int(data[pos])
+			pos += 1 + size
+			if size == 0 {
+				break
+			}
+		}
+	}
+	// 종료 블록이 없는 파일도 표준 디코더처럼 읽은 프레임까지 인정합니다.
+	return frames, nil
+}
+
+// checkAnimationBudget은 프레임을 합성하기 전에 프레임 수와 전체 픽셀 수가 제한 안에 있는지 확인합니다.
+func checkAnimationBudget(frameCount int, bounds image.Rectangle) error {
+	if frameCount > MaxAnimationFrames {
+		return Errorf(types.ErrorCodeImageTooLarge, "animation has too many frames: %d (max %d)", frameCount, MaxAnimationFrames)
+	}
+	pixels := int64(frameCount) * int64(bounds.Dx()) * int64(bounds.Dy())
+	if pixels > MaxAnimationPixels {
+		return Errorf(types.ErrorCodeImageTooLarge, "animation too large: %d frames of %dx%d (max %d pixels)", frameCount, bounds.Dx(), bounds.Dy(), MaxAnimationPixels)
+	}
+	return nil
+}
+
+// WebP RIFF 청크 식별자
+const (
+	webpChunkVP8X = "VP8X"
+	webpChunkANMF = "ANMF"
+	webpChunkALPH = "ALPH"
+	webpChunkVP8  = "VP8 "
+	webpChunkVP8L = "VP8L"
+)
+
+// webpChunk는 RIFF 청크 하나입니다.
+type webpChunk struct {
+	id   string
+	data []byte
+}
+
+// decodeWebpFrames는 애니메이션 WebP의 ANMF 프레임을 각각 디코딩해 캔버스에 합성합니다.
+// golang.org/x/image/webp는 애니메이션을 지원하지 않으므로 프레임마다 단일 이미지 WebP를 만들어 디코딩합니다.
+func decodeWebpFrames(data []byte) ([]image.Image, error) {
+	chunks, err := parseWebpChunks(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var canvasWidth, canvasHeight int
+	var anmf []webpChunk
+	for _, chunk := range chunks {
+		switch chunk.id {
+		case webpChunkVP8X:
+			if len(chunk.data) < 10 {
+				return nil, Errorf(types.ErrorCodeImageDecode, "WebP VP8X 청크 형식 오류")
+			}
+			canvasWidth = int(readUint24(chunk.data[4:])) + 1
+			canvasHeight = int(readUint24(chunk.data[7:])) + 1
+		case webpChunkANMF:
+			anmf = append(anmf, chunk)
+		}
+	}
+
+	// 애니메이션이 아니면 표준 디코더를 사용합니다.
+	if len(anmf) == 0 {
+		img, err := webp.Decode(bytes.NewReader(data))
+		if err != nil {
+			return nil, Errorf(types.ErrorCodeImageDecode, "WebP 디코딩 실패: %v", err)
+		}
+		return []image.Image{img}, nil
+	}
+
+	canvasBounds := image.Rect(0, 0, canvasWidth, canvasHeight)
+	if err := checkAnimationBudget(len(anmf), canvasBounds); err != nil {
+		return nil, err
+	}
+	canvas := image.NewRGBA(canvasBounds)
+	frames := make([]image.Image, 0, len(anmf))
+	for i, chunk := range anmf {
+		if len(chunk.data) < 16 {
+			return nil, Errorf(types.ErrorCodeImageDecode, "WebP ANMF 청크 형식 오류 (프레임 %d)", i)
+		}
+		offsetX := int(readUint24(chunk.data[0:])) * 2
+		offsetY := int(readUint24(chunk.data[3:])) * 2
+		frameWidth := int(readUint24(chunk.data[6:])) + 1
+		frameHeight := int(readUint24(chunk.data[9:])) + 1
+		flags := chunk.data[15]
+		disposeToBackground := flags&0x01 != 0
+		noBlend := flags&0x02 != 0
+
+		frameImg, err := decodeWebpFrame(chunk.data[16:], frameWidth, frameHeight)
+		if err != nil {
+			return nil, Errorf(types.ErrorCodeImageDecode, "WebP 프레임 %d 디코딩 실패: %v", i, err)
+		}
+
+		rect := image.Rect(offsetX, offsetY, offsetX+frameWidth, offsetY+frameHeight)
+		op := draw.Over
+		if noBlend {
+			op = draw.Src
+		}
+		draw.Draw(canvas, rect, frameImg, frameImg.Bounds().Min, op)
+		frames = append(frames, flattenOnWhite(canvas))
+
+		if disposeToBackground {
+			draw.Draw(canvas, rect, image.Transparent, image.Point{}, draw.Src)
+		}
+	}
+	return frames, nil
+}
+
+// decodeWebpFrame은 ANMF 프레임 데이터를 단일 이미지 WebP로 감싸 디코딩합니다.
+func decodeWebpFrame(frameData []byte, width, height int) (image.Image, error) {
+	chunks, err := parseChunkList(frameData)
+	if err != nil {
+		return nil, err
+	}
+
+	var body bytes.Buffer
+	hasAlpha := false
+	for _, chunk := range chunks {
+		if chunk.id == webpChunkALPH {
+			hasAlpha = true
+		}
+	}
+	if hasAlpha {
+		vp8x := make([]byte, 10)
+		vp8x[0] = 1 << 4 // alpha 플래그
+		putUint24(vp8x[4:], uint32(width-1))
+		putUint24(vp8x[7:], uint32(height-1))
+		writeWebpChunk(&body, webpChunkVP8X, vp8x)
+	}
+	for _, chunk := range chunks {
+		switch chunk.id {
+		case webpChunkALPH, webpChunkVP8, webpChunkVP8L:
+			writeWebpChunk(&body, chunk.id, chunk.data)
+		}
+	}
+
+	var file bytes.Buffer
+	file.WriteString("RIFF")
+	binary.Write(&file, binary.LittleEndian, uint32(4+body.Len()))
+	file.WriteString("WEBP")
+	file.Write(body.Bytes())
+	return webp.Decode(&file)
+}
+
+// parseWebpChunks는 RIFF 헤더를 확인하고 최상위 청크 목록을 반환합니다.
+func parseWebpChunks(data []byte) ([]webpChunk, error) {
+	if len(data) < 12 || string(data[0:4]) != "RIFF" || string(data[8:12]) != "WEBP" {
+		return nil, Errorf(types.ErrorCodeImageDecode, "WebP RIFF 헤더 형식 오류")
+	}
+	return parseChunkList(data[12:])
+}
+
+// parseChunkList는 연속된 RIFF 청크를 파싱합니다. 홀수 길이 청크의 패딩을 처리합니다.
+func parseChunkList(data []byte) ([]webpChunk, error) {
+	var chunks []webpChunk
+	for len(data) >= 8 {
+		id := string(data[0:4])
+		size := int(binary.LittleEndian.Uint32(data[4:8]))
+		if size < 0 || 8+size > len(data) {
+			return nil, Errorf(types.ErrorCodeImageDecode, "WebP 청크 %q 길이 오류", id)
+		}
+		chunks = append(chunks, webpChunk{id: id, data: data[8 : 8+size]})
+		next := 8 + size + size%2
+		if next > len(data) {
+			break
+		}
+		data = data[next:]
+	}
+	return chunks, nil
+}
+
+func writeWebpChunk(buf *bytes.Buffer, id string, data []byte) {
+	buf.WriteString(id)
+	binary.Write(buf, binary.LittleEndian, uint32(len(data)))
+	buf.Write(data)
+	if len(data)%2 == 1 {
+		buf.WriteByte(0)
+	}
+}
+
+func readUint24(b []byte) uint32 {
+	return uint32(b[0]) | uint32(b[1])<<8 | uint32(b[2])<<16
+}
+
+func putUint24(b []byte, v uint32) {
+	b[0] = byte(v)
+	b[1] = byte(v >> 8)
+	b[2] = byte(v >> 16)
+}
+
+// SelectRepresentativeFrames는 OCR 할 대표 프레임을 고릅니다.
+// 첫 프레임, 가운데 프레임, 대비가 가장 높은 프레임, 마지막 프레임 순으로 후보를 만들고
+// 서로 거의 같은 프레임은 제외해 최대 maxFrames 개를 반환합니다.
+func SelectRepresentativeFrames(frames []image.Image, maxFrames int) []image.Image {
+	if len(frames) <= 1 || maxFrames <= 1 {
+		if len(frames) == 0 {
+			return nil
+		}
+		return frames[:1]
+	}
+
+	bestContrast, bestIndex := -1.0, 0
+	for i, frame := range frames {
+		if c := luminanceContrast(frame); c > bestContrast {
+			bestContrast, bestIndex = c, i
+		}
+	}
+	candidates := []int{0, len(frames) / 2, bestIndex, len(frames) - 1}
+
+	var selected []image.Image
+	var hashes []uint64
+	seen := make(map[int]bool)
+	for _, idx := range candidates {
+		if seen[idx] || len(selected) >= maxFrames {
+			continue
+		}
+		seen[idx] = true
+
+		hash := averageHash(frames[idx])
+		duplicate := false
+		for _, h := range hashes {
+			if bits.OnesCount64(h^hash) <= frameHashDistanceThreshold {
+				duplicate = true
+				break
+			}
+		}
+		if duplicate {
+			continue
+		}
+		hashes = append(hashes, hash)
+		selected = append(selected, frames[idx])
+	}
+	return selected
+}
+
+// luminanceContrast는 밝기의 표준편차를 계산합니다. 큰 이미지는 샘플링합니다.
+func luminanceContrast(img image.Image) float64 {
+	bounds := img.Bounds()
+	step := max(1, max(bounds.Dx(), bounds.Dy())/200)
+
+	var sum, sumSq float64
+	var count int
+	for y := bounds.Min.Y; y < bounds.Max.Y; y += step {
+		for x := bounds.Min.X; x < bounds.Max.X; x += step {
+			l := float64(color.GrayModel.Convert(img.At(x, y)).(color.Gray).Y)
+			sum += l
+			sumSq += l * l
+			count++
+		}
+	}
+	if count == 0 {
+		return 0
+	}
+	mean := sum / float64(count)
+	return math.Sqrt(math.Max(0, sumSq/float64(count)-mean*mean))
+}
+
+// averageHash는 8x8 평균 해시를 계산합니다.
+func averageHash(img image.Image) uint64 {
+	bounds := img.Bounds()
+	if bounds.Empty() {
+		return 0
+	}
+	var values [64]float64
+	var total float64
+	for i := 0; i < 64; i++ {
+		cx, cy := i%8, i/8
+		x := bounds.Min.X + (2*cx+1)*bounds.Dx()/16
+		y := bounds.Min.Y + (2*cy+1)*bounds.Dy()/16
+		values[i] = float64(color.GrayModel.Convert(img.At(x, y)).(color.Gray).Y)
+		total += values[i]
+	}
+	mean := total / 64
+
+	var hash uint64
+	for i, v := range values {
+		if v > mean {
+			hash |= 1 << uint(i)
+		}
+	}
+	return hash
+}
+
+// flattenOnWhite는 투명 영역을 흰색으로 채운 복사본을 반환합니다.
+func flattenOnWhite(src image.Image) *image.RGBA {
+	dst := image.NewRGBA(src.Bounds())
+	draw.Draw(dst, dst.Bounds(), image.White, image.Point{}, draw.Src)
+	draw.Draw(dst, dst.Bounds(), src, src.Bounds().Min, draw.Over)
+	return dst
+}
+
+func cloneRGBA(src *image.RGBA) *image.RGBA {
+	dst := image.NewRGBA(src.Bounds())
+	copy(dst.Pix, src.Pix)
+	return dst
+}
@@ -0,0 +1,128 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/gif"
+	"testing"
+
+	types "github.com/ndns-dev/ndns-tesseract/src/types"
+)
+
+// encodeGif는 width x height 캔버스에 1x1 프레임 frameCount 개를 가진 GIF를 만듭니다.
+func encodeGif(t *testing.T, width, height, frameCount int) []byte {
+	t.Helper()
+	palette := color.Palette{color.White, color.Black}
+	g := &gif.GIF{Config: image.Config{ColorModel: palette, Width: width, Height: height}}
+	for i := 0; i < frameCount; i++ {
+		frame := image.NewPaletted(image.Rect(0, 0, 1, 1), palette)
+		frame.SetColorIndex(0, 0, uint8(i%2))
+		g.Image = append(g.Image, frame)
+		g.Delay = append(g.Delay, 10)
+	}
+	var buf bytes.Buffer
+	if err := gif.EncodeAll(&buf, g); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestDecodeAnimationFramesLimits(t *testing.T) {
+	tests := []struct {
+		name          string
+		width, height int
+		frameCount    int
+		wantFrames    int
+		wantCode      types.ErrorCode
+	}{
+		{name: "small animation", width: 100, height: 50, frameCount: 5, wantFrames: 5},
+		{name: "frame limit", width: 10, height: 10, frameCount: MaxAnimationFrames, wantFrames: MaxAnimationFrames},
+		{name: "too many frames", width: 10, height: 10, frameCount: MaxAnimationFrames + 1, wantCode: types.ErrorCodeImageTooLarge},
+		{name: "pixel budget exceeded", width: 5000, height: 5000, frameCount: 2, wantCode: types.ErrorCodeImageTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			frames, err := DecodeAnimationFrames(encodeGif(t, tt.width, tt.height, tt.frameCount), "image/gif")
+			if tt.wantCode != "" {
+				if got := ErrorCodeOf(err); got != tt.wantCode {
+					t.Fatalf("error code = %q (%v), want %q", got, err, tt.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(frames) != tt.wantFrames {
+				t.Fatalf("got %d frames, want %d", len(frames), tt.wantFrames)
+			}
+			if got := frames[0].Bounds(); got != image.Rect(0, 0, tt.width, tt.height) {
+				t.Errorf("frame bounds = %v, want %dx%d canvas", got, tt.width, tt.height)
+			}
+		})
+	}
+}
+
+func TestCountGifFrames(t *testing.T) {
+	// 프레임마다 다른 팔레트를 쓰면 인코더가 지역 색상표를 기록합니다.
+	localPalettes := &gif.GIF{}
+	for i := 0; i < 3; i++ {
+		palette := color.Palette{color.Gray{Y: uint8(i)}, color.White, color.Black}
+		localPalettes.Image = append(localPalettes.Image, image.NewPaletted(image.Rect(0, 0, 4, 4), palette))
+		localPalettes.Delay = append(localPalettes.Delay, 10)
+	}
+	var local bytes.Buffer
+	if err := gif.EncodeAll(&local, localPalettes); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		data    []byte
+		want    int
+		wantErr bool
+	}{
+		{name: "single frame", data: encodeGif(t, 10, 10, 1), want: 1},
+		{name: "global palette", data: encodeGif(t, 10, 10, 7), want: 7},
+		{name: "local palettes", data: local.Bytes(), want: 3},
+		{name: "stops counting after limit", data: encodeGif(t, 2, 2, MaxAnimationFrames+5), want: MaxAnimationFrames + 1},
+		{name: "truncated header", data: []byte("GIF89a"), wantErr: true},
+		{name: "truncated sub block", data: encodeGif(t, 10, 10, 2)[:30], wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := countGifFrames(tt.data)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("countGifFrames() = %d, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("countGifFrames() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeGifFramesRejectsBombBeforeDecoding(t *testing.T) {
+	// 1x1 프레임 두 개를 가진 GIF의 캔버스와 첫 프레임 크기를 20000x20000으로 바꿉니다.
+	// 그대로 gif.DecodeAll에 넘기면 픽셀 데이터를 읽기 전에 프레임 버퍼부터 할당합니다.
+	data := encodeGif(t, 1, 1, 2)
+	binary.LittleEndian.PutUint16(data[6:], 20000)
+	binary.LittleEndian.PutUint16(data[8:], 20000)
+	descriptor := bytes.IndexByte(data[13:], 0x2C) + 13
+	binary.LittleEndian.PutUint16(data[descriptor+5:], 20000)
+	binary.LittleEndian.PutUint16(data[descriptor+7:], 20000)
+
+	_, err := DecodeAnimationFrames(data, "image/gif")
+	if got := ErrorCodeOf(err); got != types.ErrorCodeImageTooLarge {
+		t.Fatalf("error code = %q (%v), want %q", got, err, types.ErrorCodeImageTooLarge)
+	}
+}
@@ -2,8 +2,11 @@ package utils
 
 import (
 	"context"
+	"fmt"
+	"os"
 	"sync"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 )
@@ -11,16 +14,24 @@ import (
 var (
 	dynamoOnce   sync.Once
 	dynamoClient *dynamodb.Client
+	dynamoErr    error
 )
 
 // GetDynamoDBClient는 싱글톤 DynamoDB 클라이언트를 반환합니다.
-func GetDynamoDBClient(ctx context.Context) *dynamodb.Client {
+// DYNAMODB_ENDPOINT가 있으면 해당 엔드포인트(예: DynamoDB Local http://localhost:8000)로 연결합니다.
+// AWS 설정을 불러오지 못하면 오류를 반환하며, 설정 오류는 다시 시도해도 바뀌지 않으므로 그대로 기억합니다.
+func GetDynamoDBClient(ctx context.Context) (*dynamodb.Client, error) {
 	dynamoOnce.Do(func() {
 		cfg, err := config.LoadDefaultConfig(ctx)
 		if err != nil {
-			panic(err)
+			dynamoErr = fmt.Errorf("failed to load AWS config: %w", err)
+			return
 		}
-		dynamoClient = dynamodb.NewFromConfig(cfg)
+		dynamoClient = dynamodb.NewFromConfig(cfg, func(o *dynamodb.Options) {
+			if endpoint := os.Getenv("DYNAMODB_ENDPOINT"); endpoint != "" {
+				o.BaseEndpoint = aws.String(endpoint)
+			}
+		})
 	})
-	return dynamoClient
+	return dynamoClient, dynamoErr
 }
@@ -1,29 +1,281 @@
-// utils/utils.go (또는 DownloadImage가 있던 파일)
-
 package utils
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
+	"log"
+	"net"
 	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	types "github.com/ndns-dev/ndns-tesseract/src/types"
 )
 
-// FetchImageBytes: URL에서 이미지를 다운로드하여 바이트 배열로 반환
-func FetchImageBytes(url string) ([]byte, error) {
-	resp, err := http.Get(url)
+// defaultUserAgent는 이미지 요청에 사용하는 기본 User-Agent입니다.
+const defaultUserAgent = "Mozilla/5.0 (compatible; ndns-tesseract/1.0; +https://github.com/ndns-dev)"
+
+// defaultHostHeaders는 호스트별 기본 헤더입니다. 키는 호스트 접미사입니다.
+// 네이버 이미지 CDN은 Referer가 없는 요청을 거부하는 경우가 많습니다.
+var defaultHostHeaders = map[string]map[string]string{
+	"pstatic.net": {"Referer": "https://blog.naver.com/"},
+	"naver.net":   {"Referer": "https://blog.naver.com/"},
+	"naver.com":   {"Referer": "https://blog.naver.com/"},
+}
+
+// supportedImageTypes는 OCR 대상으로 허용하는 MIME 타입입니다.
+var supportedImageTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+	"image/webp": true,
+	"image/bmp":  true,
+}
+
+// DownloadedImage는 다운로드한 이미지와 판별된 MIME 타입입니다.
+type DownloadedImage struct {
+	Url         string
+	Bytes       []byte
+	ContentType string // 내용 기반으로 판별한 MIME 타입
+}
+
+// ImageDownloader는 제한 시간, 크기 제한, 재시도와 MIME 판별을 지원하는 이미지 다운로더입니다.
+type ImageDownloader struct {
+	Client        *http.Client
+	Timeout       time.Duration                // 시도 한 번의 제한 시간
+	MaxBytes      int64                        // 허용하는 최대 응답 크기
+	MaxRetries    int                          // 첫 시도 이후 재시도 횟수
+	RetryDelay    time.Duration                // 재시도 기본 대기 시간 (지수 증가)
+	MaxRetryAfter time.Duration                // Retry-After 헤더를 따를 때 기다리는 최대 시간
+	UserAgent     string                       // User-Agent 헤더
+	HostHeaders   map[string]map[string]string // 호스트 접미사별 추가 헤더
+}
+
+// NewImageDownloader는 types의 크롤링 설정을 기본값으로 사용하는 다운로더를 생성합니다.
+func NewImageDownloader() *ImageDownloader {
+	return &ImageDownloader{
+		Client:        &http.Client{},
+		Timeout:       types.IMAGE_DOWNLOAD_TIMEOUT,
+		MaxBytes:      types.MAX_IMAGE_BYTES,
+		MaxRetries:    types.CRAWL_MAX_RETRIES,
+		RetryDelay:    types.CRAWL_RETRY_DELAY,
+		MaxRetryAfter: types.IMAGE_MAX_RETRY_AFTER,
+		UserAgent:     defaultUserAgent,
+		HostHeaders:   defaultHostHeaders,
+	}
+}
+
+// NewImageDownloaderFromEnv는 환경 변수로 기본값을 재정의한 다운로더를 생성합니다.
+// IMAGE_DOWNLOAD_TIMEOUT(Go duration), IMAGE_MAX_BYTES, IMAGE_DOWNLOAD_RETRIES,
+// IMAGE_MAX_RETRY_AFTER(Go duration)를 지원합니다.
+func NewImageDownloaderFromEnv() *ImageDownloader {
+	d := NewImageDownloader()
+	if raw := os.Getenv("IMAGE_DOWNLOAD_TIMEOUT"); raw != "" {
+		if v, err := time.ParseDuration(raw); err == nil && v > 0 {
+			d.Timeout = v
+		} else {
+			log.Printf("WARNING: Invalid IMAGE_DOWNLOAD_TIMEOUT %q, using %s", raw, d.Timeout)
+		}
+	}
+	if raw := os.Getenv("IMAGE_MAX_BYTES"); raw != "" {
+		if v, err := strconv.ParseInt(raw, 10, 64); err == nil && v > 0 {
+			d.MaxBytes = v
+		} else {
+			log.Printf("WARNING: Invalid IMAGE_MAX_BYTES %q, using %d", raw, d.MaxBytes)
+		}
+	}
+	if raw := os.Getenv("IMAGE_DOWNLOAD_RETRIES"); raw != "" {
+		if v, err := strconv.Atoi(raw); err == nil && v >= 0 {
+			d.MaxRetries = v
+		} else {
+			log.Printf("WARNING: Invalid IMAGE_DOWNLOAD_RETRIES %q, using %d", raw, d.MaxRetries)
+		}
+	}
+	if raw := os.Getenv("IMAGE_MAX_RETRY_AFTER"); raw != "" {
+		if v, err := time.ParseDuration(raw); err == nil && v >= 0 {
+			d.MaxRetryAfter = v
+		} else {
+			log.Printf("WARNING: Invalid IMAGE_MAX_RETRY_AFTER %q, using %s", raw, d.MaxRetryAfter)
+		}
+	}
+	return d
+}
+
+// retryableStatusError는 재시도 가능한 HTTP 응답 상태를 나타냅니다.
+type retryableStatusError struct {
+	StatusCode int
+	Status     string
+	RetryAfter time.Duration
+}
+
+func (e *retryableStatusError) Error() string {
+	return fmt.Sprintf("bad status code: %d %s", e.StatusCode, e.Status)
+}
+
+// Download는 URL에서 이미지를 내려받습니다.
+// 5xx, 429, 네트워크 오류는 지수 백오프로 재시도하고, 그 밖의 4xx, 크기 초과,
+// 이미지가 아닌 응답은 재시도하지 않는 코드의 AppError로 반환합니다.
+// Retry-After는 MaxRetryAfter까지만 따르며, 기다린 뒤 ctx 기한이 지나는 경우 바로 포기합니다.
+func (d *ImageDownloader) Download(ctx context.Context, imageUrl string) (*DownloadedImage, error) {
+	parsed, err := url.Parse(imageUrl)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, Errorf(types.ErrorCodeValidation, "invalid image URL: %s", imageUrl)
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= d.MaxRetries; attempt++ {
+		if attempt > 0 {
+			delay := d.retryDelay(attempt, lastErr)
+			if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+				return nil, Errorf(types.ErrorCodeImageUnavailable, "image download retry in %s exceeds remaining deadline: %w", delay, lastErr)
+			}
+			log.Printf("Retrying image download (%d/%d) in %s: %s", attempt, d.MaxRetries, delay, imageUrl)
+			select {
+			case <-ctx.Done():
+				return nil, fmt.Errorf("image download canceled: %w (last error: %v)", ctx.Err(), lastErr)
+			case <-time.After(delay):
+			}
+		}
+
+		image, err := d.downloadOnce(ctx, parsed)
+		if err == nil {
+			return image, nil
+		}
+		if !IsRetryable(err) || ctx.Err() != nil {
+			return nil, err
+		}
+		lastErr = err
+	}
+	return nil, fmt.Errorf("image download failed after %d attempts: %w", d.MaxRetries+1, lastErr)
+}
+
+// retryDelay는 attempt 번째 재시도 전에 기다릴 시간입니다.
+// 서버가 보낸 Retry-After가 지수 백오프보다 길면 MaxRetryAfter로 제한해 따릅니다.
+func (d *ImageDownloader) retryDelay(attempt int, lastErr error) time.Duration {
+	delay := d.RetryDelay * time.Duration(1<<(attempt-1))
+	var statusErr *retryableStatusError
+	if errors.As(lastErr, &statusErr) && statusErr.RetryAfter > delay {
+		delay = min(statusErr.RetryAfter, max(d.MaxRetryAfter, delay))
+	}
+	return delay
+}
+
+// downloadOnce는 한 번의 다운로드 시도를 수행합니다.
+func (d *ImageDownloader) downloadOnce(ctx context.Context, parsed *url.URL) (*DownloadedImage, error) {
+	if d.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d.Timeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, parsed.String(), nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to make HTTP GET request: %w", err)
+		return nil, Errorf(types.ErrorCodeValidation, "failed to create image request: %w", err)
+	}
+	req.Header.Set("User-Agent", d.UserAgent)
+	req.Header.Set("Accept", "image/avif,image/webp,image/png,image/jpeg,image/gif,image/*;q=0.8")
+	for key, value := range d.headersForHost(parsed.Hostname()) {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := d.Client.Do(req)
+	if err != nil {
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			return nil, Errorf(types.ErrorCodeImageTimeout, "image download timed out after %s: %w", d.Timeout, err)
+		}
+		return nil, Errorf(types.ErrorCodeImageUnavailable, "failed to make HTTP GET request: %w", err)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+		return nil, NewError(types.ErrorCodeImageUnavailable, &retryableStatusError{
+			StatusCode: resp.StatusCode,
+			Status:     resp.Status,
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+		})
+	}
+	// 429를 제외한 4xx 응답은 재시도해도 결과가 같으므로 재시도하지 않습니다.
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone {
+		return nil, Errorf(types.ErrorCodeImageNotFound, "bad status code: %d %s", resp.StatusCode, resp.Status)
+	}
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("bad status code: %d %s", resp.StatusCode, resp.Status)
+		return nil, Errorf(types.ErrorCodeImageRejected, "bad status code: %d %s", resp.StatusCode, resp.Status)
+	}
+	if d.MaxBytes > 0 && resp.ContentLength > d.MaxBytes {
+		return nil, Errorf(types.ErrorCodeImageTooLarge, "image too large: %d bytes (max %d)", resp.ContentLength, d.MaxBytes)
+	}
+
+	reader := io.Reader(resp.Body)
+	if d.MaxBytes > 0 {
+		reader = io.LimitReader(resp.Body, d.MaxBytes+1)
+	}
+	imageBytes, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, Errorf(types.ErrorCodeImageUnavailable, "failed to read image content from response body: %w", err)
+	}
+	if d.MaxBytes > 0 && int64(len(imageBytes)) > d.MaxBytes {
+		return nil, Errorf(types.ErrorCodeImageTooLarge, "image too large: more than %d bytes", d.MaxBytes)
 	}
 
-	imageBytes, err := io.ReadAll(resp.Body)
+	contentType, err := SniffImageType(imageBytes)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read image content from response body: %w", err)
+		return nil, err
 	}
 
-	return imageBytes, nil
+	return &DownloadedImage{
+		Url:         parsed.String(),
+		Bytes:       imageBytes,
+		ContentType: contentType,
+	}, nil
+}
+
+// SniffImageType은 바이트 내용으로 MIME 타입을 판별하고, 지원하지 않는 형식이면 UNSUPPORTED_IMAGE 오류를 반환합니다.
+func SniffImageType(data []byte) (string, error) {
+	if len(data) == 0 {
+		return "", Errorf(types.ErrorCodeUnsupportedImage, "empty image body")
+	}
+	contentType := http.DetectContentType(data)
+	if idx := strings.Index(contentType, ";"); idx >= 0 {
+		contentType = contentType[:idx]
+	}
+	if !supportedImageTypes[contentType] {
+		return "", Errorf(types.ErrorCodeUnsupportedImage, "unsupported content type: %s", contentType)
+	}
+	return contentType, nil
+}
+
+// headersForHost는 호스트 접미사가 일치하는 추가 헤더를 반환합니다.
+func (d *ImageDownloader) headersForHost(host string) map[string]string {
+	headers := make(map[string]string)
+	for suffix, values := range d.HostHeaders {
+		if host == suffix || strings.HasSuffix(host, "."+suffix) {
+			for key, value := range values {
+				headers[key] = value
+			}
+		}
+	}
+	return headers
+}
+
+// parseRetryAfter는 Retry-After 헤더를 now 기준 대기 시간으로 해석합니다.
+// 초 단위 값과 HTTP-date 형식을 모두 지원하며, 해석할 수 없거나 이미 지난 시각이면 0을 반환합니다.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds <= 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	at, err := http.ParseTime(value)
+	if err != nil {
+		return 0
+	}
+	return max(at.Sub(now), 0)
 }
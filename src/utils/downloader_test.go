@@ -0,0 +1,147 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	types "github.com/ndns-dev/ndns-tesseract/src/types"
+)
+
+// newRetryAfterServer는 첫 요청에 429와 Retry-After를 보내고 이후에는 PNG를 반환하는 서버입니다.
+func newRetryAfterServer(t *testing.T, retryAfter string) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, 4, 4))); err != nil {
+		t.Fatal(err)
+	}
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			w.Header().Set("Retry-After", retryAfter)
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write(buf.Bytes())
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func TestImageDownloaderRetryAfter(t *testing.T) {
+	tests := []struct {
+		name          string
+		retryAfter    string
+		maxRetryAfter time.Duration
+		deadline      time.Duration
+		wantCode      types.ErrorCode
+		wantRequests  int32
+	}{
+		{name: "clamped to max", maxRetryAfter: 10 * time.Millisecond, wantRequests: 2},
+		{name: "clamped delay fits deadline", maxRetryAfter: 10 * time.Millisecond, deadline: 5 * time.Second, wantRequests: 2},
+		{name: "http date clamped to max", retryAfter: time.Now().Add(time.Hour).UTC().Format(http.TimeFormat), maxRetryAfter: 10 * time.Millisecond, wantRequests: 2},
+		{name: "delay longer than deadline", maxRetryAfter: time.Hour, deadline: 2 * time.Second, wantCode: types.ErrorCodeImageUnavailable, wantRequests: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			retryAfter := tt.retryAfter
+			if retryAfter == "" {
+				retryAfter = "3600"
+			}
+			server, requests := newRetryAfterServer(t, retryAfter)
+			d := &ImageDownloader{
+				Client:        server.Client(),
+				Timeout:       time.Second,
+				MaxRetries:    2,
+				RetryDelay:    time.Millisecond,
+				MaxRetryAfter: tt.maxRetryAfter,
+			}
+			ctx := context.Background()
+			if tt.deadline > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.deadline)
+				defer cancel()
+			}
+
+			start := time.Now()
+			image, err := d.Download(ctx, server.URL+"/image.png")
+			if elapsed := time.Since(start); elapsed > time.Second {
+				t.Errorf("Download took %s, want Retry-After to be clamped or skipped", elapsed)
+			}
+			if tt.wantCode != "" {
+				if got := ErrorCodeOf(err); got != tt.wantCode {
+					t.Fatalf("error code = %q (%v), want %q", got, err, tt.wantCode)
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			} else if image.ContentType != "image/png" {
+				t.Errorf("content type = %q, want image/png", image.ContentType)
+			}
+			if got := requests.Load(); got != tt.wantRequests {
+				t.Errorf("got %d requests, want %d", got, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		value string
+		want  time.Duration
+	}{
+		{name: "seconds", value: "120", want: 2 * time.Minute},
+		{name: "seconds with spaces", value: " 3 ", want: 3 * time.Second},
+		{name: "zero", value: "0", want: 0},
+		{name: "negative", value: "-5", want: 0},
+		{name: "http date", value: now.Add(90 * time.Second).Format(http.TimeFormat), want: 90 * time.Second},
+		{name: "rfc 850 date", value: now.Add(time.Hour).Format(time.RFC850), want: time.Hour},
+		{name: "date in the past", value: now.Add(-time.Minute).Format(http.TimeFormat), want: 0},
+		{name: "empty", value: "", want: 0},
+		{name: "garbage", value: "soon", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseRetryAfter(tt.value, now); got != tt.want {
+				t.Errorf("parseRetryAfter(%q) = %s, want %s", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestImageDownloaderRetryDelay(t *testing.T) {
+	d := &ImageDownloader{RetryDelay: 100 * time.Millisecond, MaxRetryAfter: time.Second}
+	statusErr := func(retryAfter time.Duration) error {
+		return NewError(types.ErrorCodeImageUnavailable, &retryableStatusError{StatusCode: 429, RetryAfter: retryAfter})
+	}
+
+	tests := []struct {
+		name    string
+		attempt int
+		lastErr error
+		want    time.Duration
+	}{
+		{name: "backoff", attempt: 3, lastErr: statusErr(0), want: 400 * time.Millisecond},
+		{name: "retry-after within max", attempt: 1, lastErr: statusErr(500 * time.Millisecond), want: 500 * time.Millisecond},
+		{name: "retry-after clamped", attempt: 1, lastErr: statusErr(time.Hour), want: time.Second},
+		{name: "backoff longer than max", attempt: 5, lastErr: statusErr(time.Hour), want: 1600 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := d.retryDelay(tt.attempt, tt.lastErr); got != tt.want {
+				t.Errorf("retryDelay(%d) = %s, want %s", tt.attempt, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+
+	customTypes "github.com/ndns-dev/ndns-tesseract/src/types"
+)
+
+// AppError는 안정적인 오류 코드가 붙은 오류입니다.
+// 재시도 여부와 HTTP 상태 코드는 코드에서 결정되며, API 응답과 SQS 재시도 판단에 사용됩니다.
+type AppError struct {
+	Code customTypes.ErrorCode
+	Err  error
+}
+
+func (e *AppError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *AppError) Unwrap() error {
+	return e.Err
+}
+
+// Retryable은 재시도하면 성공할 수 있는 오류인지 반환합니다.
+func (e *AppError) Retryable() bool {
+	return e.Code.Retryable()
+}
+
+// HTTPStatus는 API 응답에 사용할 HTTP 상태 코드를 반환합니다.
+func (e *AppError) HTTPStatus() int {
+	return e.Code.HTTPStatus()
+}
+
+// NewError는 err에 오류 코드를 붙입니다. err가 nil이면 nil을 반환합니다.
+func NewError(code customTypes.ErrorCode, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &AppError{Code: code, Err: err}
+}
+
+// Errorf는 형식 문자열로 코드가 붙은 오류를 생성합니다.
+func Errorf(code customTypes.ErrorCode, format string, args ...interface{}) error {
+	return &AppError{Code: code, Err: fmt.Errorf(format, args...)}
+}
+
+// AsAppError는 오류 체인에서 가장 바깥쪽 AppError를 찾습니다.
+func AsAppError(err error) (*AppError, bool) {
+	var appErr *AppError
+	if errors.As(err, &appErr) {
+		return appErr, true
+	}
+	return nil, false
+}
+
+// ErrorCodeOf는 오류의 코드를 반환합니다. 코드가 없는 오류는 INTERNAL_ERROR입니다.
+func ErrorCodeOf(err error) customTypes.ErrorCode {
+	if appErr, ok := AsAppError(err); ok {
+		return appErr.Code
+	}
+	return customTypes.ErrorCodeInternal
+}
+
+// IsRetryable은 오류가 재시도 대상인지 확인합니다. 코드가 없는 오류는 재시도 대상입니다.
+func IsRetryable(err error) bool {
+	return err != nil && ErrorCodeOf(err).Retryable()
+}
+
+// HTTPStatusOf는 오류에 대응하는 HTTP 상태 코드를 반환합니다.
+func HTTPStatusOf(err error) int {
+	return ErrorCodeOf(err).HTTPStatus()
+}
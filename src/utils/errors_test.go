@@ -0,0 +1,67 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	customTypes "github.com/ndns-dev/ndns-tesseract/src/types"
+)
+
+func TestErrorCodeMapping(t *testing.T) {
+	tests := []struct {
+		code          customTypes.ErrorCode
+		wantStatus    int
+		wantRetryable bool
+	}{
+		{customTypes.ErrorCodeInvalidRequest, http.StatusBadRequest, false},
+		{customTypes.ErrorCodeValidation, http.StatusBadRequest, false},
+		{customTypes.ErrorCodeImageNotFound, http.StatusUnprocessableEntity, false},
+		{customTypes.ErrorCodeImageRejected, http.StatusUnprocessableEntity, false},
+		{customTypes.ErrorCodeImageTimeout, http.StatusGatewayTimeout, true},
+		{customTypes.ErrorCodeImageUnavailable, http.StatusBadGateway, true},
+		{customTypes.ErrorCodeImageTooLarge, http.StatusRequestEntityTooLarge, false},
+		{customTypes.ErrorCodeUnsupportedImage, http.StatusUnsupportedMediaType, false},
+		{customTypes.ErrorCodeImageDecode, http.StatusUnprocessableEntity, false},
+		{customTypes.ErrorCodeOcrEngine, http.StatusInternalServerError, true},
+		{customTypes.ErrorCodeAnalyzeApi, http.StatusBadGateway, true},
+		{customTypes.ErrorCodeAnalyzeApiRejected, http.StatusBadGateway, false},
+		{customTypes.ErrorCodePersistence, http.StatusServiceUnavailable, true},
+		{customTypes.ErrorCodeInternal, http.StatusInternalServerError, true},
+		// 알 수 없는 코드는 재시도 대상인 500으로 취급합니다.
+		{customTypes.ErrorCode("UNKNOWN"), http.StatusInternalServerError, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.code), func(t *testing.T) {
+			// 오류 체인 안쪽에 있는 코드도 찾아야 합니다.
+			err := fmt.Errorf("wrapped: %w", Errorf(tt.code, "failed"))
+			if got := ErrorCodeOf(err); got != tt.code {
+				t.Errorf("ErrorCodeOf() = %q, want %q", got, tt.code)
+			}
+			if got := HTTPStatusOf(err); got != tt.wantStatus {
+				t.Errorf("HTTPStatusOf() = %d, want %d", got, tt.wantStatus)
+			}
+			if got := IsRetryable(err); got != tt.wantRetryable {
+				t.Errorf("IsRetryable() = %t, want %t", got, tt.wantRetryable)
+			}
+		})
+	}
+}
+
+func TestErrorCodeOfUncodedError(t *testing.T) {
+	err := errors.New("boom")
+	if got := ErrorCodeOf(err); got != customTypes.ErrorCodeInternal {
+		t.Errorf("ErrorCodeOf() = %q, want %q", got, customTypes.ErrorCodeInternal)
+	}
+	if !IsRetryable(err) {
+		t.Error("IsRetryable() = false, want true for an uncoded error")
+	}
+	if IsRetryable(nil) {
+		t.Error("IsRetryable(nil) = true, want false")
+	}
+	if NewError(customTypes.ErrorCodeValidation, nil) != nil {
+		t.Error("NewError(code, nil) != nil")
+	}
+}
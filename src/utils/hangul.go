@@ -0,0 +1,118 @@
+package utils
+
+import (
+	"unicode"
+)
+
+// 한글 음절 분해에 사용하는 유니코드 상수
+const (
+	hangulSyllableBase  = 0xAC00
+	hangulSyllableLast  = 0xD7A3
+	hangulJungseongSize = 21
+	hangulJongseongSize = 28
+)
+
+var (
+	hangulChoseong  = []rune("ㄱㄲㄴㄷㄸㄹㅁㅂㅃㅅㅆㅇㅈㅉㅊㅋㅌㅍㅎ")
+	hangulJungseong = []rune("ㅏㅐㅑㅒㅓㅔㅕㅖㅗㅘㅙㅚㅛㅜㅝㅞㅟㅠㅡㅢㅣ")
+	hangulJongseong = []rune("ㄱㄲㄳㄴㄵㄶㄷㄹㄺㄻㄼㄽㄾㄿㅀㅁㅂㅄㅅㅆㅇㅈㅊㅋㅌㅍㅎ")
+)
+
+// IsHangulSyllable은 rune이 완성형 한글 음절인지 확인합니다.
+func IsHangulSyllable(r rune) bool {
+	return r >= hangulSyllableBase && r <= hangulSyllableLast
+}
+
+// DecomposeHangul은 한글 음절을 초성/중성/종성 자모로 분해합니다.
+// 한글이 아닌 문자는 소문자로 변환해 그대로 반환합니다.
+func DecomposeHangul(r rune) []rune {
+	if !IsHangulSyllable(r) {
+		return []rune{unicode.ToLower(r)}
+	}
+	offset := int(r - hangulSyllableBase)
+	cho := offset / (hangulJungseongSize * hangulJongseongSize)
+	jung := (offset % (hangulJungseongSize * hangulJongseongSize)) / hangulJongseongSize
+	jong := offset % hangulJongseongSize
+
+	jamo := []rune{hangulChoseong[cho], hangulJungseong[jung]}
+	if jong > 0 {
+		jamo = append(jamo, hangulJongseong[jong-1])
+	}
+	return jamo
+}
+
+// JamoSequence는 매칭용으로 정규화된 텍스트의 자모 시퀀스입니다.
+// Origins[i]는 Jamo[i]가 유래한 Runes의 인덱스입니다.
+type JamoSequence struct {
+	Runes   []rune
+	Jamo    []rune
+	Origins []int
+}
+
+// NewJamoSequence는 공백과 문장부호를 제거한 뒤 텍스트를 자모 단위로 분해합니다.
+// OCR 결과는 띄어쓰기가 불안정하므로 문자와 숫자만 남깁니다.
+func NewJamoSequence(text string) JamoSequence {
+	var seq JamoSequence
+	for _, r := range text {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			continue
+		}
+		idx := len(seq.Runes)
+		seq.Runes = append(seq.Runes, r)
+		for _, j := range DecomposeHangul(r) {
+			seq.Jamo = append(seq.Jamo, j)
+			seq.Origins = append(seq.Origins, idx)
+		}
+	}
+	return seq
+}
+
+// SubstringEditDistance는 pattern과 text의 임의 부분 문자열 사이의 최소 편집 거리를 계산합니다.
+// 반환값은 거리와 가장 잘 맞는 부분 문자열의 [start, end) 범위입니다.
+func SubstringEditDistance(pattern, text []rune) (distance, start, end int) {
+	m, n := len(pattern), len(text)
+	if m == 0 {
+		return 0, 0, 0
+	}
+	if n == 0 {
+		return m, 0, 0
+	}
+
+	// prev/cur[j]: pattern[:i]와 text[s:j]의 최소 거리, starts[j]: 그때의 s
+	prev := make([]int, n+1)
+	cur := make([]int, n+1)
+	prevStart := make([]int, n+1)
+	curStart := make([]int, n+1)
+	for j := 0; j <= n; j++ {
+		prevStart[j] = j
+	}
+
+	for i := 1; i <= m; i++ {
+		cur[0] = i
+		curStart[0] = 0
+		for j := 1; j <= n; j++ {
+			cost := 1
+			if pattern[i-1] == text[j-1] {
+				cost = 0
+			}
+			best, bestStart := prev[j-1]+cost, prevStart[j-1]
+			if v := prev[j] + 1; v < best {
+				best, bestStart = v, prevStart[j]
+			}
+			if v := cur[j-1] + 1; v < best {
+				best, bestStart = v, curStart[j-1]
+			}
+			cur[j], curStart[j] = best, bestStart
+		}
+		prev, cur = cur, prev
+		prevStart, curStart = curStart, prevStart
+	}
+
+	distance, end = prev[0], 0
+	for j := 1; j <= n; j++ {
+		if prev[j] < distance {
+			distance, end = prev[j], j
+		}
+	}
+	return distance, prevStart[end], end
+}
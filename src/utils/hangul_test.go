@@ -0,0 +1,88 @@
+package utils
+
+import "testing"
+
+func TestDecomposeHangul(t *testing.T) {
+	tests := []struct {
+		in   rune
+		want string
+	}{
+		{in: '가', want: "ㄱㅏ"},
+		{in: '한', want: "ㅎㅏㄴ"},
+		{in: '찬', want: "ㅊㅏㄴ"},
+		{in: '힣', want: "ㅎㅣㅎ"},
+		{in: '읽', want: "ㅇㅣㄺ"},
+		{in: 'A', want: "a"},
+		{in: '7', want: "7"},
+		{in: 'ㄱ', want: "ㄱ"},
+	}
+
+	for _, tt := range tests {
+		if got := string(DecomposeHangul(tt.in)); got != tt.want {
+			t.Errorf("DecomposeHangul(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestNewJamoSequence(t *testing.T) {
+	seq := NewJamoSequence("협 찬!A")
+	if got := string(seq.Runes); got != "협찬A" {
+		t.Errorf("Runes = %q, want %q", got, "협찬A")
+	}
+	if got := string(seq.Jamo); got != "ㅎㅕㅂㅊㅏㄴa" {
+		t.Errorf("Jamo = %q, want %q", got, "ㅎㅕㅂㅊㅏㄴa")
+	}
+	wantOrigins := []int{0, 0, 0, 1, 1, 1, 2}
+	if len(seq.Origins) != len(wantOrigins) {
+		t.Fatalf("Origins = %v, want %v", seq.Origins, wantOrigins)
+	}
+	for i := range wantOrigins {
+		if seq.Origins[i] != wantOrigins[i] {
+			t.Fatalf("Origins = %v, want %v", seq.Origins, wantOrigins)
+		}
+	}
+}
+
+func TestSubstringEditDistance(t *testing.T) {
+	tests := []struct {
+		name         string
+		pattern      string
+		text         string
+		wantDistance int
+		wantMatch    string
+	}{
+		{name: "empty pattern", pattern: "", text: "abc", wantDistance: 0, wantMatch: ""},
+		{name: "empty text", pattern: "abc", text: "", wantDistance: 3, wantMatch: ""},
+		{name: "exact substring", pattern: "bcd", text: "abcde", wantDistance: 0, wantMatch: "bcd"},
+		{name: "one substitution", pattern: "bxd", text: "abcde", wantDistance: 1, wantMatch: "bcd"},
+		{name: "one deletion in text", pattern: "bcd", text: "abde", wantDistance: 1},
+		{name: "one insertion in text", pattern: "bcd", text: "abcxde", wantDistance: 1},
+		{name: "no overlap", pattern: "xyz", text: "abc", wantDistance: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text := []rune(tt.text)
+			distance, start, end := SubstringEditDistance([]rune(tt.pattern), text)
+			if distance != tt.wantDistance {
+				t.Errorf("distance = %d, want %d", distance, tt.wantDistance)
+			}
+			if start < 0 || end < start || end > len(text) {
+				t.Fatalf("range [%d, %d) is outside the text", start, end)
+			}
+			if tt.wantMatch != "" && string(text[start:end]) != tt.wantMatch {
+				t.Errorf("match = %q, want %q", string(text[start:end]), tt.wantMatch)
+			}
+		})
+	}
+}
+
+func TestSubstringEditDistanceOnJamo(t *testing.T) {
+	// OCR이 '원고료'의 '료'를 '로'로 읽은 경우 음절 단위로는 한 글자 차이지만 자모 단위로는 모음 하나 차이입니다.
+	pattern := NewJamoSequence("소정의 원고료").Jamo
+	text := NewJamoSequence("이 글은 소정의 원고로를 받아 작성했습니다").Jamo
+	distance, _, _ := SubstringEditDistance(pattern, text)
+	if distance != 1 {
+		t.Errorf("distance = %d, want 1", distance)
+	}
+}
@@ -2,84 +2,177 @@ package utils
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
-	"fmt"
+	"errors"
 	"log"
-	"net/url"
+	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambdacontext"
 	customTypes "github.com/ndns-dev/ndns-tesseract/src/types"
 )
 
-func ParseFormURLEncoded(body string) map[string]string {
-	result := make(map[string]string)
-	values, err := url.ParseQuery(body)
-	if err != nil {
-		return result
+// ResponseFormat은 Lambda가 반환해야 하는 HTTP 응답 이벤트 형식입니다.
+type ResponseFormat string
+
+const (
+	ResponseFormatAPIGatewayV1 ResponseFormat = "apigateway-v1" // REST API (events.APIGatewayProxyResponse)
+	ResponseFormatAPIGatewayV2 ResponseFormat = "apigateway-v2" // HTTP API (events.APIGatewayV2HTTPResponse)
+	ResponseFormatFunctionURL  ResponseFormat = "function-url"  // Lambda Function URL (events.LambdaFunctionURLResponse)
+)
+
+// RequestIdHeader는 응답에 요청 ID를 싣는 헤더입니다.
+const RequestIdHeader = "X-Request-Id"
+
+// RequestInfo는 응답을 만들 때 필요한 요청 정보입니다.
+type RequestInfo struct {
+	RequestId  string
+	ReceivedAt time.Time
+	Format     ResponseFormat
+}
+
+type requestInfoKey struct{}
+
+// WithRequestInfo는 요청 정보를 컨텍스트에 저장합니다.
+// RequestId가 비어 있으면 Lambda 요청 ID 또는 새로 생성한 ID를 사용합니다.
+func WithRequestInfo(ctx context.Context, info RequestInfo) context.Context {
+	return context.WithValue(ctx, requestInfoKey{}, completeRequestInfo(ctx, info))
+}
+
+// RequestInfoFrom은 컨텍스트의 요청 정보를 반환합니다. 없으면 기본값으로 채운 정보를 반환합니다.
+func RequestInfoFrom(ctx context.Context) RequestInfo {
+	if info, ok := ctx.Value(requestInfoKey{}).(RequestInfo); ok {
+		return info
 	}
-	for k, v := range values {
-		if len(v) > 0 {
-			result[k] = v[0]
+	return completeRequestInfo(ctx, RequestInfo{})
+}
+
+// completeRequestInfo는 비어 있는 요청 정보 필드를 기본값으로 채웁니다.
+func completeRequestInfo(ctx context.Context, info RequestInfo) RequestInfo {
+	if info.RequestId == "" {
+		if lc, ok := lambdacontext.FromContext(ctx); ok && lc.AwsRequestID != "" {
+			info.RequestId = lc.AwsRequestID
+		} else {
+			info.RequestId = NewRequestId()
 		}
 	}
-	return result
+	if info.ReceivedAt.IsZero() {
+		info.ReceivedAt = time.Now()
+	}
+	if info.Format == "" {
+		info.Format = ResponseFormatAPIGatewayV1
+	}
+	return info
+}
+
+// NewRequestId는 임의의 요청 ID를 생성합니다.
+func NewRequestId() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 36)
+	}
+	return hex.EncodeToString(b[:])
 }
 
-func Response(data interface{}, err error) (interface{}, error) {
+// Response는 처리 결과를 ApiResponse 본문의 HTTP 응답 이벤트로 변환합니다.
+// 오류 응답의 상태 코드는 오류 코드에 대응하는 HTTP 상태 코드이며,
+// 응답 형식은 컨텍스트의 요청 정보(API Gateway v1/v2, Function URL)를 따릅니다.
+func Response(ctx context.Context, data interface{}, err error) (interface{}, error) {
+	info := RequestInfoFrom(ctx)
+	body := customTypes.ApiResponse{
+		Meta: customTypes.ResponseMeta{
+			RequestId:  info.RequestId,
+			ReceivedAt: info.ReceivedAt,
+			DurationMs: time.Since(info.ReceivedAt).Milliseconds(),
+		},
+	}
+
+	statusCode := http.StatusOK
+	if errResp, ok := data.(*customTypes.ErrorResponse); ok && errResp != nil {
+		body.Error = errResp
+		statusCode = errResp.ErrorCode.HTTPStatus()
+	} else if err != nil {
+		body.Error = newErrorResponse(err)
+		statusCode = body.Error.ErrorCode.HTTPStatus()
+	} else {
+		body.Success = true
+		body.Data = data
+	}
+
+	return JSONResponse(info, statusCode, body), nil
+}
+
+// JSONResponse는 본문을 JSON으로 직렬화해 요청 형식에 맞는 응답 이벤트를 만듭니다.
+// 직렬화에 실패하면 INTERNAL_ERROR 응답을 반환합니다.
+func JSONResponse(info RequestInfo, statusCode int, body interface{}) interface{} {
+	encoded, err := json.Marshal(body)
 	if err != nil {
-		if errResp, ok := data.(*customTypes.ErrorResponse); ok && errResp != nil {
-			return &events.APIGatewayProxyResponse{
-				StatusCode: 400,
-				Body:       fmt.Sprintf(`{"message": "%s", "jobId": "%s", "imageUrl": "%s", "errorCode": "%s"}`, errResp.Message, errResp.JobId, errResp.ImageURL, errResp.ErrorCode),
-				Headers: map[string]string{
-					"Content-Type": "application/json",
-				},
-			}, nil
-		}
-		return &events.APIGatewayProxyResponse{
-			StatusCode: 500,
-			Body:       fmt.Sprintf(`{"message": "%s"}`, err.Error()),
-			Headers: map[string]string{
-				"Content-Type": "application/json",
+		log.Printf("ERROR: Failed to marshal response: %v", err)
+		statusCode = http.StatusInternalServerError
+		encoded, _ = json.Marshal(customTypes.ApiResponse{
+			Error: &customTypes.ErrorResponse{
+				Message:   "failed to marshal response",
+				ErrorCode: customTypes.ErrorCodeInternal,
+				Retryable: customTypes.ErrorCodeInternal.Retryable(),
 			},
-		}, nil
+			Meta: customTypes.ResponseMeta{RequestId: info.RequestId, ReceivedAt: info.ReceivedAt},
+		})
 	}
 
-	var responseBody string
-	if data != nil {
-		jsonData, err := json.Marshal(data)
-		if err != nil {
-			return &events.APIGatewayProxyResponse{
-				StatusCode: 500,
-				Body:       fmt.Sprintf(`{"message": "Failed to marshal response: %s"}`, err.Error()),
-				Headers: map[string]string{
-					"Content-Type": "application/json",
-				},
-			}, nil
+	headers := map[string]string{
+		"Content-Type":  "application/json",
+		RequestIdHeader: info.RequestId,
+	}
+	switch info.Format {
+	case ResponseFormatAPIGatewayV2:
+		return &events.APIGatewayV2HTTPResponse{
+			StatusCode: statusCode,
+			Headers:    headers,
+			Body:       string(encoded),
+		}
+	case ResponseFormatFunctionURL:
+		return &events.LambdaFunctionURLResponse{
+			StatusCode: statusCode,
+			Headers:    headers,
+			Body:       string(encoded),
+		}
+	default:
+		return &events.APIGatewayProxyResponse{
+			StatusCode: statusCode,
+			Headers:    headers,
+			Body:       string(encoded),
 		}
-		responseBody = string(jsonData)
-	} else {
-		responseBody = `{"message": "success"}`
 	}
-
-	return &events.APIGatewayProxyResponse{
-		StatusCode: 200,
-		Body:       responseBody,
-		Headers: map[string]string{
-			"Content-Type": "application/json",
-		},
-	}, nil
 }
 
+// ErrorHandler는 오류를 로그와 웹훅으로 남기고 오류 코드가 포함된 ErrorResponse를 만듭니다.
+// source는 오류가 발생한 위치로 로그에만 사용됩니다.
 func ErrorHandler(ctx context.Context, err error, jobId, imageURL, source string) (*customTypes.ErrorResponse, error) {
 	errMsg := err.Error()
-	log.Printf("FINAL ERROR in %s: %s (jobId: %s, ImageURL: %s)", source, errMsg, jobId, imageURL)
-	errData := &customTypes.ErrorResponse{
-		Message:   errMsg,
-		JobId:     jobId,
-		ImageURL:  imageURL,
-		ErrorCode: source,
-	}
-	WebhookLog("ERROR: %s", errData)
+	code := ErrorCodeOf(err)
+	log.Printf("FINAL ERROR in %s [%s]: %s (jobId: %s, ImageURL: %s)", source, code, errMsg, jobId, imageURL)
+	errData := newErrorResponse(err)
+	errData.JobId = jobId
+	errData.ImageURL = imageURL
+	WebhookLog("ERROR: %v", errData)
 	return errData, nil
 }
+
+// newErrorResponse는 오류의 코드, 재시도 여부, 필드별 검증 오류로 ErrorResponse를 만듭니다.
+func newErrorResponse(err error) *customTypes.ErrorResponse {
+	code := ErrorCodeOf(err)
+	errResp := &customTypes.ErrorResponse{
+		Message:   err.Error(),
+		ErrorCode: code,
+		Retryable: code.Retryable(),
+	}
+	var fieldErrs customTypes.FieldErrors
+	if errors.As(err, &fieldErrs) {
+		errResp.Fields = fieldErrs
+	}
+	return errResp
+}
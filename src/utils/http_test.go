@@ -0,0 +1,123 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	customTypes "github.com/ndns-dev/ndns-tesseract/src/types"
+)
+
+// responseParts는 형식별 응답 이벤트에서 상태 코드, 헤더, 본문을 꺼냅니다.
+func responseParts(t *testing.T, resp interface{}, format ResponseFormat) (int, map[string]string, string) {
+	t.Helper()
+	switch format {
+	case ResponseFormatAPIGatewayV2:
+		r, ok := resp.(*events.APIGatewayV2HTTPResponse)
+		if !ok {
+			t.Fatalf("response = %T, want *events.APIGatewayV2HTTPResponse", resp)
+		}
+		return r.StatusCode, r.Headers, r.Body
+	case ResponseFormatFunctionURL:
+		r, ok := resp.(*events.LambdaFunctionURLResponse)
+		if !ok {
+			t.Fatalf("response = %T, want *events.LambdaFunctionURLResponse", resp)
+		}
+		return r.StatusCode, r.Headers, r.Body
+	default:
+		r, ok := resp.(*events.APIGatewayProxyResponse)
+		if !ok {
+			t.Fatalf("response = %T, want *events.APIGatewayProxyResponse", resp)
+		}
+		return r.StatusCode, r.Headers, r.Body
+	}
+}
+
+func TestResponse(t *testing.T) {
+	// 따옴표, 역슬래시, 줄바꿈이 섞인 메시지도 유효한 JSON으로 직렬화되어야 합니다.
+	message := "bad \"value\"\nat C:\\path"
+
+	tests := []struct {
+		name        string
+		format      ResponseFormat
+		data        interface{}
+		err         error
+		wantStatus  int
+		wantSuccess bool
+		wantCode    customTypes.ErrorCode
+	}{
+		{name: "v1 success", format: ResponseFormatAPIGatewayV1, data: map[string]string{"text": message}, wantStatus: http.StatusOK, wantSuccess: true},
+		{name: "v2 success", format: ResponseFormatAPIGatewayV2, data: map[string]string{"text": message}, wantStatus: http.StatusOK, wantSuccess: true},
+		{name: "function url success", format: ResponseFormatFunctionURL, data: map[string]string{"text": message}, wantStatus: http.StatusOK, wantSuccess: true},
+		{name: "coded error", format: ResponseFormatAPIGatewayV2, err: Errorf(customTypes.ErrorCodeValidation, "%s", message), wantStatus: http.StatusBadRequest, wantCode: customTypes.ErrorCodeValidation},
+		{name: "uncoded error", format: ResponseFormatFunctionURL, err: context.DeadlineExceeded, wantStatus: http.StatusInternalServerError, wantCode: customTypes.ErrorCodeInternal},
+		{
+			name:       "error response data",
+			format:     ResponseFormatAPIGatewayV1,
+			data:       &customTypes.ErrorResponse{Message: message, ErrorCode: customTypes.ErrorCodeImageTooLarge},
+			wantStatus: http.StatusRequestEntityTooLarge,
+			wantCode:   customTypes.ErrorCodeImageTooLarge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := WithRequestInfo(context.Background(), RequestInfo{RequestId: "req-1", ReceivedAt: time.Now(), Format: tt.format})
+			resp, err := Response(ctx, tt.data, tt.err)
+			if err != nil {
+				t.Fatalf("Response() error = %v", err)
+			}
+			status, headers, body := responseParts(t, resp, tt.format)
+			if status != tt.wantStatus {
+				t.Errorf("StatusCode = %d, want %d", status, tt.wantStatus)
+			}
+			if headers["Content-Type"] != "application/json" || headers[RequestIdHeader] != "req-1" {
+				t.Errorf("Headers = %v", headers)
+			}
+
+			var got struct {
+				Success bool                       `json:"success"`
+				Data    map[string]string          `json:"data"`
+				Error   *customTypes.ErrorResponse `json:"error"`
+				Meta    customTypes.ResponseMeta   `json:"meta"`
+			}
+			if err := json.Unmarshal([]byte(body), &got); err != nil {
+				t.Fatalf("body is not valid JSON: %v\n%s", err, body)
+			}
+			if got.Success != tt.wantSuccess || got.Meta.RequestId != "req-1" {
+				t.Errorf("body = %s", body)
+			}
+			if tt.wantSuccess {
+				if got.Data["text"] != message {
+					t.Errorf("data.text = %q, want %q", got.Data["text"], message)
+				}
+				return
+			}
+			if got.Error == nil {
+				t.Fatalf("body has no error: %s", body)
+			}
+			if got.Error.ErrorCode != tt.wantCode {
+				t.Errorf("error.errorCode = %q, want %q", got.Error.ErrorCode, tt.wantCode)
+			}
+			if tt.err != nil && got.Error.Message != tt.err.Error() {
+				t.Errorf("error.message = %q, want %q", got.Error.Message, tt.err.Error())
+			}
+		})
+	}
+}
+
+func TestJSONResponseMarshalFailure(t *testing.T) {
+	info := RequestInfo{RequestId: "req-1", Format: ResponseFormatAPIGatewayV2}
+	resp := JSONResponse(info, http.StatusOK, map[string]interface{}{"bad": make(chan int)})
+	status, _, body := responseParts(t, resp, info.Format)
+	if status != http.StatusInternalServerError {
+		t.Errorf("StatusCode = %d, want %d", status, http.StatusInternalServerError)
+	}
+	var got customTypes.ApiResponse
+	if err := json.Unmarshal([]byte(body), &got); err != nil || got.Error == nil || got.Error.ErrorCode != customTypes.ErrorCodeInternal {
+		t.Errorf("body = %s (%v), want an INTERNAL_ERROR response", body, err)
+	}
+}
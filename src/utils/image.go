@@ -1,14 +1,18 @@
 package utils
 
 import (
+	"bytes"
 	"fmt"
 	"image"
 	"image/draw"
-	"image/jpeg"
-	"os"
-	"path/filepath"
+	_ "image/gif"
+	_ "image/jpeg"
+	"image/png"
+	"log"
 
 	types "github.com/ndns-dev/ndns-tesseract/src/types"
+	_ "golang.org/x/image/bmp"
+	_ "golang.org/x/image/webp"
 )
 
 // ImageDimensions는 이미지의 가로/세로 크기 정보를 담고 있습니다
@@ -17,211 +21,213 @@ type ImageDimensions struct {
 	Height int
 }
 
-// GetImageDimensions는 이미지 파일의 가로/세로 크기를 반환합니다
-func GetImageDimensions(filePath string) (*ImageDimensions, error) {
-	// 파일 열기
-	file, err := os.Open(filePath)
-	if err != nil {
-		return nil, fmt.Errorf("파일 열기 실패: %v", err)
+// GetImageDimensions는 이미지의 가로/세로 크기를 반환합니다
+func GetImageDimensions(img image.Image) ImageDimensions {
+	bounds := img.Bounds()
+	return ImageDimensions{
+		Width:  bounds.Dx(),
+		Height: bounds.Dy(),
 	}
-	defer file.Close()
+}
 
-	// 표준 image 패키지를 사용하여 이미지 디코딩
-	img, _, err := image.Decode(file)
-	if err != nil {
-		return nil, fmt.Errorf("이미지 디코딩 실패: %v", err)
-	}
+// ImageStage는 메모리 상의 이미지를 변환하는 전처리 단계입니다.
+type ImageStage interface {
+	// Name은 로그에 표시할 단계 이름을 반환합니다.
+	Name() string
+	// Apply는 이미지를 변환합니다. 변환이 필요 없으면 입력을 그대로 반환할 수 있습니다.
+	Apply(img image.Image) (image.Image, error)
+}
 
-	// 이미지 크기 반환
-	bounds := img.Bounds()
-	return &ImageDimensions{
-		Width:  bounds.Max.X - bounds.Min.X,
-		Height: bounds.Max.Y - bounds.Min.Y,
-	}, nil
+// imageStageFunc는 함수를 ImageStage로 사용하기 위한 어댑터입니다.
+type imageStageFunc struct {
+	name string
+	fn   func(image.Image) (image.Image, error)
 }
 
-// CropImageTop은 큰 이미지를 상단 일부만 잘라서 새 파일로 저장합니다
-func CropImageTop(sourcePath string, maxHeight int) (string, error) {
-	// 원본 파일 열기
-	file, err := os.Open(sourcePath)
-	if err != nil {
-		return "", fmt.Errorf("원본 파일 열기 실패: %v", err)
-	}
-	defer file.Close()
+func (s imageStageFunc) Name() string {
+	return s.name
+}
 
-	// 이미지 디코딩
-	sourceImg, _, err := image.Decode(file)
-	if err != nil {
-		return "", fmt.Errorf("이미지 디코딩 실패: %v", err)
-	}
+func (s imageStageFunc) Apply(img image.Image) (image.Image, error) {
+	return s.fn(img)
+}
 
-	// 원본 이미지 크기 확인
-	bounds := sourceImg.Bounds()
-	width := bounds.Max.X - bounds.Min.X
-	height := bounds.Max.Y - bounds.Min.Y
+// NewImageStage는 이름과 변환 함수로 ImageStage를 생성합니다.
+func NewImageStage(name string, fn func(image.Image) (image.Image, error)) ImageStage {
+	return imageStageFunc{name: name, fn: fn}
+}
 
-	// 이미지가 이미 maxHeight 이하면 원본 반환
-	if height <= maxHeight {
-		return sourcePath, nil
-	}
+// ImagePipeline은 이미지를 한 번 디코딩해 여러 단계를 차례로 적용하고 한 번만 인코딩합니다.
+// 모든 처리는 메모리에서 이루어지며 임시 파일을 만들지 않습니다.
+type ImagePipeline struct {
+	stages []ImageStage
+}
 
-	// 잘라낼 영역 계산 (상단 maxHeight 픽셀)
-	cropRect := image.Rect(0, 0, width, maxHeight)
+// NewImagePipeline은 주어진 단계로 파이프라인을 생성합니다.
+func NewImagePipeline(stages ...ImageStage) *ImagePipeline {
+	return &ImagePipeline{stages: stages}
+}
 
-	// 새 이미지 생성
-	croppedImg := image.NewRGBA(cropRect)
+// Then은 현재 단계 뒤에 단계를 추가한 새 파이프라인을 반환합니다.
+func (p *ImagePipeline) Then(stages ...ImageStage) *ImagePipeline {
+	combined := make([]ImageStage, 0, len(p.stages)+len(stages))
+	combined = append(combined, p.stages...)
+	combined = append(combined, stages...)
+	return &ImagePipeline{stages: combined}
+}
 
-	// 원본 이미지에서 상단 부분만 새 이미지로 복사
-	draw.Draw(croppedImg, cropRect, sourceImg, bounds.Min, draw.Src)
+// Stages는 파이프라인 단계 이름 목록을 반환합니다.
+func (p *ImagePipeline) Stages() []string {
+	names := make([]string, 0, len(p.stages))
+	for _, stage := range p.stages {
+		names = append(names, stage.Name())
+	}
+	return names
+}
 
-	// 새 파일 이름 생성 (원본 파일명_cropped)
-	croppedPath := sourcePath + "_cropped.jpg"
+// Apply는 모든 단계를 차례로 적용합니다.
+func (p *ImagePipeline) Apply(img image.Image) (image.Image, error) {
+	for _, stage := range p.stages {
+		out, err := stage.Apply(img)
+		if err != nil {
+			return nil, fmt.Errorf("이미지 전처리 단계 %s 실패: %v", stage.Name(), err)
+		}
+		img = out
+	}
+	return img, nil
+}
 
-	// 새 파일 생성
-	outFile, err := os.Create(croppedPath)
+// Process는 이미지 바이트를 디코딩해 모든 단계를 적용한 뒤 PNG로 인코딩합니다.
+func (p *ImagePipeline) Process(data []byte) ([]byte, error) {
+	img, _, err := DecodeImage(data)
 	if err != nil {
-		return "", fmt.Errorf("출력 파일 생성 실패: %v", err)
+		return nil, err
 	}
-	defer outFile.Close()
-
-	// 이미지를 JPEG 형식으로 저장 (간단함을 위해 JPEG만 지원)
-	err = jpeg.Encode(outFile, croppedImg, &jpeg.Options{Quality: 90})
+	out, err := p.Apply(img)
 	if err != nil {
-		return "", fmt.Errorf("이미지 인코딩 실패: %v", err)
+		return nil, err
 	}
-
-	return croppedPath, nil
+	return EncodePNG(out)
 }
 
-// CropImageCenter는 가로가 긴 이미지의 가운데 부분만 잘라서 새 파일로 저장합니다
-func CropImageCenter(sourcePath string, cropWidth int) (string, error) {
-	// 원본 파일 열기
-	file, err := os.Open(sourcePath)
+// DecodeImage는 이미지 바이트를 디코딩합니다. JPEG, PNG, GIF, WebP, BMP를 지원합니다.
+// 작은 파일이 거대한 픽셀 버퍼로 풀리는 것을 막기 위해 헤더의 크기가 MAX_IMAGE_SIZE 픽셀을 넘으면
+// 픽셀 데이터를 디코딩하지 않고 IMAGE_TOO_LARGE 오류를 반환합니다.
+func DecodeImage(data []byte) (image.Image, string, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
 	if err != nil {
-		return "", fmt.Errorf("원본 파일 열기 실패: %v", err)
+		return nil, "", Errorf(types.ErrorCodeImageDecode, "이미지 디코딩 실패: %v", err)
+	}
+	if pixels := int64(config.Width) * int64(config.Height); pixels > types.MAX_IMAGE_SIZE {
+		return nil, "", Errorf(types.ErrorCodeImageTooLarge, "image too large: %dx%d (max %d pixels)", config.Width, config.Height, types.MAX_IMAGE_SIZE)
 	}
-	defer file.Close()
 
-	// 이미지 디코딩
-	sourceImg, _, err := image.Decode(file)
+	img, format, err := image.Decode(bytes.NewReader(data))
 	if err != nil {
-		return "", fmt.Errorf("이미지 디코딩 실패: %v", err)
+		return nil, "", Errorf(types.ErrorCodeImageDecode, "이미지 디코딩 실패: %v", err)
 	}
+	return img, format, nil
+}
 
-	// 원본 이미지 크기 확인
-	bounds := sourceImg.Bounds()
-	width := bounds.Max.X - bounds.Min.X
-	height := bounds.Max.Y - bounds.Min.Y
-
-	// 가로 길이가 충분히 길지 않으면 원본 반환
-	targetWidth := width - (cropWidth * 2)
-	if targetWidth <= 0 {
-		return sourcePath, nil
+// EncodePNG는 이미지를 무손실 PNG 바이트로 인코딩합니다.
+func EncodePNG(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := png.Encoder{CompressionLevel: png.BestSpeed}
+	if err := encoder.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("PNG 인코딩 실패: %v", err)
 	}
+	return buf.Bytes(), nil
+}
 
-	// 잘라낼 영역 계산 (가운데 부분)
-	cropRect := image.Rect(cropWidth, 0, width-cropWidth, height)
-
-	// 새 이미지 생성
-	croppedImg := image.NewRGBA(image.Rect(0, 0, targetWidth, height))
-
-	// 원본 이미지에서 가운데 부분만 새 이미지로 복사
-	draw.Draw(croppedImg, croppedImg.Bounds(), sourceImg, cropRect.Min, draw.Src)
-
-	// 새 파일 이름 생성 (원본 파일명_center_cropped)
-	ext := filepath.Ext(sourcePath)
-	basePath := sourcePath[:len(sourcePath)-len(ext)]
-	croppedPath := basePath + "_center_cropped.jpg"
-
-	// 새 파일 생성
-	outFile, err := os.Create(croppedPath)
-	if err != nil {
-		return "", fmt.Errorf("출력 파일 생성 실패: %v", err)
+// CropImage는 이미지에서 rect 영역을 잘라냅니다. rect는 이미지 좌표계 기준이며 이미지 범위로 제한됩니다.
+// 가능한 경우 픽셀을 복사하지 않는 SubImage를 사용합니다.
+func CropImage(img image.Image, rect image.Rectangle) image.Image {
+	rect = rect.Intersect(img.Bounds())
+	if sub, ok := img.(interface {
+		SubImage(r image.Rectangle) image.Image
+	}); ok {
+		return sub.SubImage(rect)
 	}
-	defer outFile.Close()
 
-	// 이미지를 JPEG 형식으로 저장
-	err = jpeg.Encode(outFile, croppedImg, &jpeg.Options{Quality: 90})
-	if err != nil {
-		return "", fmt.Errorf("이미지 인코딩 실패: %v", err)
-	}
+	cropped := image.NewRGBA(rect)
+	draw.Draw(cropped, rect, img, rect.Min, draw.Src)
+	return cropped
+}
 
-	return croppedPath, nil
+// CropTopStage는 세로가 maxHeight보다 긴 이미지의 상단 부분만 남기는 단계입니다.
+func CropTopStage(maxHeight int) ImageStage {
+	return NewImageStage(fmt.Sprintf("crop-top(%d)", maxHeight), func(img image.Image) (image.Image, error) {
+		bounds := img.Bounds()
+		if bounds.Dy() <= maxHeight {
+			return img, nil
+		}
+		return CropImage(img, image.Rect(bounds.Min.X, bounds.Min.Y, bounds.Max.X, bounds.Min.Y+maxHeight)), nil
+	})
 }
 
-// CropImageOptimal은 이미지 비율에 따라 최적의 방식으로 크롭합니다.
+// CropCenterStage는 좌우에서 cropWidth 픽셀씩 잘라 가운데 부분만 남기는 단계입니다.
+func CropCenterStage(cropWidth int) ImageStage {
+	return NewImageStage(fmt.Sprintf("crop-center(%d)", cropWidth), func(img image.Image) (image.Image, error) {
+		bounds := img.Bounds()
+		// 가로 길이가 충분히 길지 않으면 원본 반환
+		if bounds.Dx()-(cropWidth*2) <= 0 {
+			return img, nil
+		}
+		return CropImage(img, image.Rect(bounds.Min.X+cropWidth, bounds.Min.Y, bounds.Max.X-cropWidth, bounds.Max.Y)), nil
+	})
+}
+
+// OptimalCropStage는 이미지 비율에 따라 최적의 방식으로 크롭하는 단계입니다.
 // 세로가 긴 이미지는 상단 부분만, 가로가 긴 이미지는 가운데 부분만 잘라냅니다.
-func CropImageOptimal(sourcePath string) (string, error) {
-	// 원본 파일 열기 및 이미지 크기 확인
-	dimensions, err := GetImageDimensions(sourcePath)
-	if err != nil {
-		return "", fmt.Errorf("이미지 크기 확인 실패: %v", err)
-	}
+func OptimalCropStage() ImageStage {
+	return NewImageStage("optimal-crop", cropOptimal)
+}
 
+func cropOptimal(img image.Image) (image.Image, error) {
+	dimensions := GetImageDimensions(img)
 	width := dimensions.Width
 	height := dimensions.Height
+	if width == 0 || height == 0 {
+		return img, nil
+	}
 
 	// 비율에 따라 다른 크롭 방식 적용
 	aspectRatio := float64(width) / float64(height)
 	isWideTooMuch := width > types.OPTIMAL_WIDTH*1.5   // 너비가 최적값의 1.5배 이상
 	isTallTooMuch := height > types.OPTIMAL_HEIGHT*1.5 // 높이가 최적값의 1.5배 이상
 
-	fmt.Printf("이미지 크기: %dx%d, 비율: %.2f\n", width, height, aspectRatio)
+	log.Printf("이미지 크기: %dx%d, 비율: %.2f", width, height, aspectRatio)
 
 	// 이미지가 이미 적정 크기면 원본 반환
 	if width <= types.OPTIMAL_WIDTH && height <= types.OPTIMAL_HEIGHT {
-		return sourcePath, nil
+		return img, nil
 	}
 
-	var croppedPath string
-
 	// 가로가 매우 긴 경우 (가로 > 세로*2): 가운데 부분 크롭
 	if aspectRatio > 2.0 && isWideTooMuch {
-		fmt.Printf("가로가 매우 긴 이미지: 가운데 부분 %d픽셀 크롭\n", types.CROP_WIDTH)
-		croppedPath, err = CropImageCenter(sourcePath, types.CROP_WIDTH)
-		if err != nil {
-			return "", err
-		}
+		log.Printf("가로가 매우 긴 이미지: 가운데 부분 %d픽셀 크롭", types.CROP_WIDTH)
+		cropped, _ := CropCenterStage(types.CROP_WIDTH).Apply(img)
 
 		// 크롭 후에도 세로가 너무 길면 상단 부분도 크롭
-		newDimensions, _ := GetImageDimensions(croppedPath)
-		if newDimensions != nil && newDimensions.Height > types.OPTIMAL_HEIGHT*1.5 {
-			fmt.Printf("세로도 긴 이미지: 상단 %d픽셀만 사용\n", types.CROP_HEIGHT)
-			return CropImageTop(croppedPath, types.CROP_HEIGHT)
+		if GetImageDimensions(cropped).Height > types.OPTIMAL_HEIGHT*1.5 {
+			log.Printf("세로도 긴 이미지: 상단 %d픽셀만 사용", types.CROP_HEIGHT)
+			return CropTopStage(types.CROP_HEIGHT).Apply(cropped)
 		}
-
-		return croppedPath, nil
+		return cropped, nil
 	} else if aspectRatio < 1.0 && isTallTooMuch {
 		// 세로가 매우 긴 경우: 상단 부분 크롭
-		fmt.Printf("세로가 긴 이미지: 상단 %d픽셀만 사용\n", types.CROP_HEIGHT)
-		return CropImageTop(sourcePath, types.CROP_HEIGHT)
+		log.Printf("세로가 긴 이미지: 상단 %d픽셀만 사용", types.CROP_HEIGHT)
+		return CropTopStage(types.CROP_HEIGHT).Apply(img)
 	} else if aspectRatio > 1.0 && aspectRatio < 2.0 && isWideTooMuch {
 		// 가로가 약간 긴 경우 (1.0 < 비율 < 2.0): 너비가 너무 넓으면 가운데 크롭
 		// 너비를 적절히 줄이기 위한 크롭 범위 계산
 		cropAmount := (width - types.OPTIMAL_WIDTH) / 2
 		if cropAmount > 0 {
-			fmt.Printf("가로가 약간 긴 이미지: 좌우 각각 %d픽셀 제거\n", cropAmount)
-			return CropImageCenter(sourcePath, cropAmount)
+			log.Printf("가로가 약간 긴 이미지: 좌우 각각 %d픽셀 제거", cropAmount)
+			return CropCenterStage(cropAmount).Apply(img)
 		}
 	}
 
 	// 특별한 경우가 아니면 원본 반환
-	return sourcePath, nil
-}
-
-// IsGifImage는 파일이 GIF 이미지인지 확인합니다 (파일 시그니처 검사)
-func IsGifImage(filePath string) bool {
-	file, err := os.Open(filePath)
-	if err != nil {
-		return false
-	}
-	defer file.Close()
-
-	// GIF 파일 시그니처 확인 (GIF87a 또는 GIF89a)
-	header := make([]byte, 6)
-	if _, err := file.Read(header); err != nil {
-		return false
-	}
-
-	return string(header) == "GIF87a" || string(header) == "GIF89a"
+	return img, nil
 }
@@ -0,0 +1,455 @@
+package utils
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"sort"
+
+	types "github.com/ndns-dev/ndns-tesseract/src/types"
+	xdraw "golang.org/x/image/draw"
+)
+
+// 전처리 단계 기본 설정
+const (
+	DefaultSauvolaWindow = 25   // Sauvola 이진화 창 크기 (픽셀)
+	DefaultSauvolaK      = 0.2  // Sauvola 민감도
+	sauvolaDynamicRange  = 128  // 표준편차의 동적 범위
+	DefaultDeskewAngle   = 5.0  // 기울기 보정 탐색 범위 (도)
+	deskewAngleStep      = 0.25 // 기울기 탐색 간격 (도)
+	deskewSamplePixels   = 20000
+	maxUpscaleFactor     = 4.0
+	inkRowMinRatio       = 0.005 // 글자가 있는 행으로 보는 최소 잉크 비율
+)
+
+// NewOcrImagePipeline은 크롭 뒤에 요청별 향상 단계를 붙인 OCR 전처리 파이프라인을 생성합니다.
+// 단계 순서: 크롭 → 흑백 → 노이즈 제거 → 반전 → 기울기 보정 → 확대 → 이진화
+// 타일 모드(기본값)에서는 크롭하지 않으며, 큰 이미지는 호출하는 쪽에서 TileImage로 나눈 뒤 타일마다 적용합니다.
+func NewOcrImagePipeline(options types.PreprocessOptions) *ImagePipeline {
+	var stages []ImageStage
+	switch options.Crop {
+	case types.CropOptimal:
+		stages = append(stages, OptimalCropStage())
+	case types.CropTop:
+		stages = append(stages, CropTopStage(types.CROP_HEIGHT))
+	case types.CropCenter:
+		stages = append(stages, CropCenterStage(types.CROP_WIDTH))
+	}
+	if options.Grayscale {
+		stages = append(stages, GrayscaleStage())
+	}
+	if options.Denoise {
+		stages = append(stages, DenoiseStage())
+	}
+	if options.AutoInvert {
+		stages = append(stages, AutoInvertStage())
+	}
+	if options.Deskew {
+		stages = append(stages, DeskewStage(DefaultDeskewAngle))
+	}
+	if options.Upscale {
+		target := options.TargetTextHeight
+		if target <= 0 {
+			target = types.DEFAULT_TARGET_TEXT_HEIGHT
+		}
+		stages = append(stages, UpscaleStage(target))
+	}
+	switch options.Binarize {
+	case types.BinarizeOtsu:
+		stages = append(stages, OtsuThresholdStage())
+	case types.BinarizeSauvola:
+		stages = append(stages, SauvolaThresholdStage(DefaultSauvolaWindow, DefaultSauvolaK))
+	}
+	return NewImagePipeline(stages...)
+}
+
+// GrayscaleStage는 이미지를 8비트 흑백으로 변환하는 단계입니다.
+func GrayscaleStage() ImageStage {
+	return NewImageStage("grayscale", func(img image.Image) (image.Image, error) {
+		return toGray(img), nil
+	})
+}
+
+// OtsuThresholdStage는 Otsu 방식의 전역 임계값으로 이진화하는 단계입니다.
+func OtsuThresholdStage() ImageStage {
+	return NewImageStage("otsu", func(img image.Image) (image.Image, error) {
+		gray := toGray(img)
+		return applyThreshold(gray, OtsuThreshold(gray)), nil
+	})
+}
+
+// SauvolaThresholdStage는 지역 평균/표준편차 기반의 Sauvola 적응형 이진화 단계입니다.
+// 조명이 고르지 않은 배너 이미지에 적합합니다.
+func SauvolaThresholdStage(window int, k float64) ImageStage {
+	return NewImageStage(fmt.Sprintf("sauvola(%d,%.2f)", window, k), func(img image.Image) (image.Image, error) {
+		return sauvola(toGray(img), window, k), nil
+	})
+}
+
+// AutoInvertStage는 어두운 배경에 밝은 글자가 있는 이미지를 반전하는 단계입니다.
+// 배경(다수 픽셀)이 어두우면 반전해 Tesseract가 기대하는 흰 배경/검은 글자로 맞추고,
+// 반전하지 않으면 입력 이미지를 색상 그대로 반환합니다.
+func AutoInvertStage() ImageStage {
+	return NewImageStage("auto-invert", func(img image.Image) (image.Image, error) {
+		gray := toGray(img)
+		if !IsDarkBackground(gray) {
+			return img, nil
+		}
+		inverted := image.NewGray(gray.Bounds())
+		forEachGray(gray, func(x, y int, v uint8) {
+			inverted.Pix[inverted.PixOffset(x, y)] = 255 - v
+		})
+		return inverted, nil
+	})
+}
+
+// DenoiseStage는 3x3 중간값 필터로 점 잡음을 제거하는 단계입니다.
+func DenoiseStage() ImageStage {
+	return NewImageStage("denoise", func(img image.Image) (image.Image, error) {
+		return medianFilter3(toGray(img)), nil
+	})
+}
+
+// UpscaleStage는 추정한 글자 높이가 targetTextHeight보다 작으면 이미지를 확대하는 단계입니다.
+// 확대 배율은 최대 4배이며 결과 픽셀 수는 MAX_IMAGE_SIZE를 넘지 않습니다.
+func UpscaleStage(targetTextHeight int) ImageStage {
+	return NewImageStage(fmt.Sprintf("upscale(%d)", targetTextHeight), func(img image.Image) (image.Image, error) {
+		gray := toGray(img)
+		textHeight := EstimateTextHeight(gray)
+		if textHeight <= 0 || textHeight >= targetTextHeight {
+			return gray, nil
+		}
+
+		bounds := gray.Bounds()
+		scale := math.Min(float64(targetTextHeight)/float64(textHeight), maxUpscaleFactor)
+		maxScale := math.Sqrt(float64(types.MAX_IMAGE_SIZE) / float64(bounds.Dx()*bounds.Dy()))
+		scale = math.Min(scale, maxScale)
+		if scale <= 1.05 {
+			return gray, nil
+		}
+
+		width := int(math.Round(float64(bounds.Dx()) * scale))
+		height := int(math.Round(float64(bounds.Dy()) * scale))
+		scaled := image.NewGray(image.Rect(0, 0, width, height))
+		xdraw.CatmullRom.Scale(scaled, scaled.Bounds(), gray, bounds, xdraw.Src, nil)
+		return scaled, nil
+	})
+}
+
+// DeskewStage는 수평 투영 분산이 최대가 되는 각도를 찾아 기울어진 글자를 바로 세우는 단계입니다.
+func DeskewStage(maxAngle float64) ImageStage {
+	return NewImageStage(fmt.Sprintf("deskew(%.1f)", maxAngle), func(img image.Image) (image.Image, error) {
+		gray := toGray(img)
+		angle := EstimateSkewAngle(gray, maxAngle)
+		if math.Abs(angle) < deskewAngleStep {
+			return gray, nil
+		}
+		return rotateGray(gray, -angle, EstimateBackground(gray)), nil
+	})
+}
+
+// OtsuThreshold는 클래스 간 분산을 최대화하는 전역 임계값을 계산합니다.
+func OtsuThreshold(gray *image.Gray) uint8 {
+	var histogram [256]int
+	total := 0
+	forEachGray(gray, func(_, _ int, v uint8) {
+		histogram[v]++
+		total++
+	})
+	if total == 0 {
+		return 128
+	}
+
+	var sumAll float64
+	for i, count := range histogram {
+		sumAll += float64(i * count)
+	}
+
+	var sumBackground, bestVariance float64
+	var weightBackground int
+	best := 128
+	for t := 0; t < 256; t++ {
+		weightBackground += histogram[t]
+		if weightBackground == 0 {
+			continue
+		}
+		weightForeground := total - weightBackground
+		if weightForeground == 0 {
+			break
+		}
+		sumBackground += float64(t * histogram[t])
+		meanBackground := sumBackground / float64(weightBackground)
+		meanForeground := (sumAll - sumBackground) / float64(weightForeground)
+		variance := float64(weightBackground) * float64(weightForeground) * (meanBackground - meanForeground) * (meanBackground - meanForeground)
+		if variance > bestVariance {
+			bestVariance = variance
+			best = t
+		}
+	}
+	return uint8(best)
+}
+
+// IsDarkBackground는 Otsu 임계값 기준으로 어두운 픽셀이 과반이면 true를 반환합니다.
+func IsDarkBackground(gray *image.Gray) bool {
+	threshold := OtsuThreshold(gray)
+	dark, total := 0, 0
+	forEachGray(gray, func(_, _ int, v uint8) {
+		if v <= threshold {
+			dark++
+		}
+		total++
+	})
+	return total > 0 && dark*2 > total
+}
+
+// EstimateBackground는 Otsu 임계값으로 나눈 두 쪽 중 픽셀이 많은 쪽(배경)의 평균 밝기를 반환합니다.
+func EstimateBackground(gray *image.Gray) uint8 {
+	threshold := OtsuThreshold(gray)
+	var darkSum, lightSum, dark, light int
+	forEachGray(gray, func(_, _ int, v uint8) {
+		if v <= threshold {
+			darkSum += int(v)
+			dark++
+		} else {
+			lightSum += int(v)
+			light++
+		}
+	})
+	switch {
+	case dark == 0 && light == 0:
+		return 255
+	case dark > light:
+		return uint8(darkSum / dark)
+	default:
+		return uint8(lightSum / light)
+	}
+}
+
+// EstimateTextHeight는 수평 투영에서 잉크가 있는 연속 행 구간의 중간값 높이를 글자 높이로 추정합니다.
+// 글자 행을 찾지 못하면 0을 반환합니다.
+func EstimateTextHeight(gray *image.Gray) int {
+	bounds := gray.Bounds()
+	if bounds.Empty() {
+		return 0
+	}
+	threshold := OtsuThreshold(gray)
+	inkIsDark := !IsDarkBackground(gray)
+	minInk := max(1, int(float64(bounds.Dx())*inkRowMinRatio))
+
+	var runs []int
+	run := 0
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		ink := 0
+		row := gray.Pix[gray.PixOffset(bounds.Min.X, y) : gray.PixOffset(bounds.Min.X, y)+bounds.Dx()]
+		for _, v := range row {
+			if (v <= threshold) == inkIsDark {
+				ink++
+			}
+		}
+		if ink >= minInk {
+			run++
+			continue
+		}
+		if run >= 3 {
+			runs = append(runs, run)
+		}
+		run = 0
+	}
+	if run >= 3 {
+		runs = append(runs, run)
+	}
+	if len(runs) == 0 {
+		return 0
+	}
+	sort.Ints(runs)
+	return runs[len(runs)/2]
+}
+
+// EstimateSkewAngle은 ±maxAngle 범위에서 잉크 픽셀의 수평 투영 분산이 최대가 되는 각도(도)를 찾습니다.
+func EstimateSkewAngle(gray *image.Gray, maxAngle float64) float64 {
+	bounds := gray.Bounds()
+	threshold := OtsuThreshold(gray)
+	inkIsDark := !IsDarkBackground(gray)
+
+	// 잉크 픽셀 좌표를 샘플링합니다.
+	var points [][2]float64
+	step := max(1, int(math.Sqrt(float64(bounds.Dx()*bounds.Dy())/deskewSamplePixels)))
+	cx := float64(bounds.Min.X+bounds.Max.X) / 2
+	cy := float64(bounds.Min.Y+bounds.Max.Y) / 2
+	for y := bounds.Min.Y; y < bounds.Max.Y; y += step {
+		for x := bounds.Min.X; x < bounds.Max.X; x += step {
+			if (gray.GrayAt(x, y).Y <= threshold) == inkIsDark {
+				points = append(points, [2]float64{float64(x) - cx, float64(y) - cy})
+			}
+		}
+	}
+	if len(points) < 50 {
+		return 0
+	}
+
+	diagonal := int(math.Hypot(float64(bounds.Dx()), float64(bounds.Dy()))) + 1
+	histogram := make([]int, diagonal)
+	bestAngle, bestScore := 0.0, -1.0
+	for angle := -maxAngle; angle <= maxAngle+1e-9; angle += deskewAngleStep {
+		rad := angle * math.Pi / 180
+		sin, cos := math.Sin(rad), math.Cos(rad)
+		for i := range histogram {
+			histogram[i] = 0
+		}
+		for _, p := range points {
+			bin := int(-p[0]*sin+p[1]*cos) + diagonal/2
+			if bin >= 0 && bin < diagonal {
+				histogram[bin]++
+			}
+		}
+		var score float64
+		for _, count := range histogram {
+			score += float64(count * count)
+		}
+		if score > bestScore {
+			bestScore, bestAngle = score, angle
+		}
+	}
+	return bestAngle
+}
+
+// toGray는 이미지를 *image.Gray로 변환합니다. 이미 흑백이면 그대로 반환합니다.
+func toGray(img image.Image) *image.Gray {
+	if gray, ok := img.(*image.Gray); ok {
+		return gray
+	}
+	bounds := img.Bounds()
+	gray := image.NewGray(bounds)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			gray.SetGray(x, y, color.GrayModel.Convert(img.At(x, y)).(color.Gray))
+		}
+	}
+	return gray
+}
+
+// forEachGray는 흑백 이미지의 모든 픽셀을 순회합니다.
+func forEachGray(gray *image.Gray, fn func(x, y int, v uint8)) {
+	bounds := gray.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		offset := gray.PixOffset(bounds.Min.X, y)
+		for x := 0; x < bounds.Dx(); x++ {
+			fn(bounds.Min.X+x, y, gray.Pix[offset+x])
+		}
+	}
+}
+
+// applyThreshold는 임계값 이하를 검정, 초과를 흰색으로 만듭니다.
+func applyThreshold(gray *image.Gray, threshold uint8) *image.Gray {
+	out := image.NewGray(gray.Bounds())
+	forEachGray(gray, func(x, y int, v uint8) {
+		if v > threshold {
+			out.Pix[out.PixOffset(x, y)] = 255
+		}
+	})
+	return out
+}
+
+// sauvola는 적분 영상으로 창 내 평균/표준편차를 구해 픽셀별 임계값을 적용합니다.
+func sauvola(gray *image.Gray, window int, k float64) *image.Gray {
+	bounds := gray.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	out := image.NewGray(bounds)
+	if w == 0 || h == 0 {
+		return out
+	}
+
+	// 적분 영상 (크기 (w+1)x(h+1))
+	sum := make([]float64, (w+1)*(h+1))
+	sumSq := make([]float64, (w+1)*(h+1))
+	for y := 0; y < h; y++ {
+		var rowSum, rowSumSq float64
+		offset := gray.PixOffset(bounds.Min.X, bounds.Min.Y+y)
+		for x := 0; x < w; x++ {
+			v := float64(gray.Pix[offset+x])
+			rowSum += v
+			rowSumSq += v * v
+			idx := (y+1)*(w+1) + x + 1
+			sum[idx] = sum[idx-(w+1)] + rowSum
+			sumSq[idx] = sumSq[idx-(w+1)] + rowSumSq
+		}
+	}
+
+	half := max(1, window/2)
+	for y := 0; y < h; y++ {
+		y0, y1 := max(0, y-half), min(h, y+half+1)
+		inOffset := gray.PixOffset(bounds.Min.X, bounds.Min.Y+y)
+		outOffset := out.PixOffset(bounds.Min.X, bounds.Min.Y+y)
+		for x := 0; x < w; x++ {
+			x0, x1 := max(0, x-half), min(w, x+half+1)
+			area := float64((x1 - x0) * (y1 - y0))
+			s := sum[y1*(w+1)+x1] - sum[y0*(w+1)+x1] - sum[y1*(w+1)+x0] + sum[y0*(w+1)+x0]
+			sq := sumSq[y1*(w+1)+x1] - sumSq[y0*(w+1)+x1] - sumSq[y1*(w+1)+x0] + sumSq[y0*(w+1)+x0]
+			mean := s / area
+			std := math.Sqrt(math.Max(0, sq/area-mean*mean))
+			threshold := mean * (1 + k*(std/sauvolaDynamicRange-1))
+			if float64(gray.Pix[inOffset+x]) > threshold {
+				out.Pix[outOffset+x] = 255
+			}
+		}
+	}
+	return out
+}
+
+// medianFilter3은 3x3 중간값 필터를 적용합니다. 가장자리는 가장 가까운 픽셀로 채웁니다.
+func medianFilter3(gray *image.Gray) *image.Gray {
+	bounds := gray.Bounds()
+	out := image.NewGray(bounds)
+	var window [9]uint8
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			n := 0
+			for dy := -1; dy <= 1; dy++ {
+				for dx := -1; dx <= 1; dx++ {
+					sx := min(max(x+dx, bounds.Min.X), bounds.Max.X-1)
+					sy := min(max(y+dy, bounds.Min.Y), bounds.Max.Y-1)
+					window[n] = gray.Pix[gray.PixOffset(sx, sy)]
+					n++
+				}
+			}
+			sortUint8s(window[:])
+			out.Pix[out.PixOffset(x, y)] = window[4]
+		}
+	}
+	return out
+}
+
+// sortUint8s는 작은 배열을 삽입 정렬합니다.
+func sortUint8s(values []uint8) {
+	for i := 1; i < len(values); i++ {
+		for j := i; j > 0 && values[j-1] > values[j]; j-- {
+			values[j-1], values[j] = values[j], values[j-1]
+		}
+	}
+}
+
+// rotateGray는 이미지를 중심 기준으로 angle(도)만큼 회전합니다. 빈 영역은 fill 값으로 채웁니다.
+func rotateGray(gray *image.Gray, angle float64, fill uint8) *image.Gray {
+	bounds := gray.Bounds()
+	out := image.NewGray(bounds)
+	rad := angle * math.Pi / 180
+	sin, cos := math.Sin(rad), math.Cos(rad)
+	cx := float64(bounds.Min.X+bounds.Max.X) / 2
+	cy := float64(bounds.Min.Y+bounds.Max.Y) / 2
+
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			// 출력 좌표를 역회전해 원본 좌표를 구합니다.
+			dx, dy := float64(x)-cx, float64(y)-cy
+			sx := int(math.Round(dx*cos + dy*sin + cx))
+			sy := int(math.Round(-dx*sin + dy*cos + cy))
+			v := fill
+			if image.Pt(sx, sy).In(bounds) {
+				v = gray.Pix[gray.PixOffset(sx, sy)]
+			}
+			out.Pix[out.PixOffset(x, y)] = v
+		}
+	}
+	return out
+}
@@ -0,0 +1,228 @@
+package utils
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"testing"
+)
+
+// newGrayFilled는 value로 채운 width x height 흑백 이미지를 만듭니다.
+func newGrayFilled(width, height int, value uint8) *image.Gray {
+	gray := image.NewGray(image.Rect(0, 0, width, height))
+	for i := range gray.Pix {
+		gray.Pix[i] = value
+	}
+	return gray
+}
+
+// fillRect는 rect 영역을 value로 채웁니다.
+func fillRect(gray *image.Gray, rect image.Rectangle, value uint8) {
+	rect = rect.Intersect(gray.Bounds())
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			gray.Pix[gray.PixOffset(x, y)] = value
+		}
+	}
+}
+
+// textLines는 background 위에 높이 lineHeight인 글자 줄 모양의 막대를 lineGap 간격으로 그립니다.
+func textLines(width, height, lineHeight, lineGap int, background, ink uint8) *image.Gray {
+	gray := newGrayFilled(width, height, background)
+	for y := lineGap; y+lineHeight < height-lineGap; y += lineHeight + lineGap {
+		fillRect(gray, image.Rect(width/10, y, width*9/10, y+lineHeight), ink)
+	}
+	return gray
+}
+
+func TestOtsuThreshold(t *testing.T) {
+	// 배경 200과 글자 50으로 나뉜 이미지의 임계값은 두 값 사이여야 합니다.
+	gray := textLines(100, 100, 8, 12, 200, 50)
+	threshold := OtsuThreshold(gray)
+	if threshold < 50 || threshold >= 200 {
+		t.Fatalf("OtsuThreshold() = %d, want in [50, 200)", threshold)
+	}
+
+	binary := applyThreshold(gray, threshold)
+	forEachGray(gray, func(x, y int, v uint8) {
+		want := uint8(255)
+		if v == 50 {
+			want = 0
+		}
+		if got := binary.GrayAt(x, y).Y; got != want {
+			t.Fatalf("pixel (%d,%d) = %d, want %d", x, y, got, want)
+		}
+	})
+
+	if got := OtsuThreshold(image.NewGray(image.Rect(0, 0, 0, 0))); got != 128 {
+		t.Errorf("OtsuThreshold(empty) = %d, want 128", got)
+	}
+}
+
+func TestSauvolaUnevenLighting(t *testing.T) {
+	// 왼쪽은 어둡고(배경 100, 글자 40) 오른쪽은 밝은(배경 220, 글자 160) 조명입니다.
+	// 전역 임계값으로는 왼쪽 배경과 오른쪽 글자를 함께 나눌 수 없습니다.
+	gray := newGrayFilled(200, 60, 100)
+	fillRect(gray, image.Rect(100, 0, 200, 60), 220)
+	fillRect(gray, image.Rect(20, 28, 80, 31), 40)
+	fillRect(gray, image.Rect(120, 28, 180, 31), 160)
+
+	out := sauvola(gray, DefaultSauvolaWindow, DefaultSauvolaK)
+	tests := []struct {
+		name string
+		at   image.Point
+		want uint8
+	}{
+		{name: "dark side text", at: image.Pt(50, 29), want: 0},
+		{name: "bright side text", at: image.Pt(150, 29), want: 0},
+		{name: "dark side background", at: image.Pt(50, 10), want: 255},
+		{name: "bright side background", at: image.Pt(150, 10), want: 255},
+		{name: "background next to text", at: image.Pt(50, 33), want: 255},
+	}
+	for _, tt := range tests {
+		if got := out.GrayAt(tt.at.X, tt.at.Y).Y; got != tt.want {
+			t.Errorf("%s: pixel %v = %d, want %d", tt.name, tt.at, got, tt.want)
+		}
+	}
+}
+
+func TestEstimateSkewAngle(t *testing.T) {
+	for _, angle := range []float64{-3, -1.5, 0, 2, 4} {
+		skewed := rotateGray(textLines(300, 200, 6, 14, 255, 0), angle, 255)
+		if got := EstimateSkewAngle(skewed, DefaultDeskewAngle); math.Abs(got-angle) > 2*deskewAngleStep {
+			t.Errorf("EstimateSkewAngle(rotated %.2f) = %.2f", angle, got)
+		}
+	}
+
+	// 잉크가 거의 없으면 기울기를 추정하지 않습니다.
+	if got := EstimateSkewAngle(newGrayFilled(100, 100, 255), DefaultDeskewAngle); got != 0 {
+		t.Errorf("EstimateSkewAngle(blank) = %.2f, want 0", got)
+	}
+}
+
+func TestDeskewStage(t *testing.T) {
+	tests := []struct {
+		name       string
+		background uint8
+		ink        uint8
+	}{
+		{name: "light background", background: 255, ink: 0},
+		{name: "dark background", background: 20, ink: 230},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			skewed := rotateGray(textLines(300, 200, 6, 14, tt.background, tt.ink), 3, tt.background)
+			out, err := DeskewStage(DefaultDeskewAngle).Apply(skewed)
+			if err != nil {
+				t.Fatal(err)
+			}
+			gray := out.(*image.Gray)
+			if got := EstimateSkewAngle(gray, DefaultDeskewAngle); math.Abs(got) > 2*deskewAngleStep {
+				t.Errorf("skew after DeskewStage = %.2f, want about 0", got)
+			}
+			// 회전으로 생긴 모서리는 흰색이 아닌 배경색으로 채워야 합니다.
+			for _, corner := range []image.Point{{0, 0}, {299, 0}, {0, 199}, {299, 199}} {
+				if got := gray.GrayAt(corner.X, corner.Y).Y; got != tt.background {
+					t.Errorf("corner %v = %d, want background %d", corner, got, tt.background)
+				}
+			}
+		})
+	}
+}
+
+func TestEstimateBackground(t *testing.T) {
+	tests := []struct {
+		name string
+		img  *image.Gray
+		want uint8
+	}{
+		{name: "light", img: textLines(100, 100, 8, 12, 240, 10), want: 240},
+		{name: "dark", img: textLines(100, 100, 8, 12, 15, 250), want: 15},
+		{name: "uniform", img: newGrayFilled(10, 10, 90), want: 90},
+		{name: "empty", img: image.NewGray(image.Rect(0, 0, 0, 0)), want: 255},
+	}
+	for _, tt := range tests {
+		if got := EstimateBackground(tt.img); got != tt.want {
+			t.Errorf("%s: EstimateBackground() = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestUpscaleStage(t *testing.T) {
+	tests := []struct {
+		name      string
+		img       *image.Gray
+		target    int
+		wantWidth int
+	}{
+		{name: "small text", img: textLines(100, 80, 8, 12, 255, 0), target: 24, wantWidth: 300},
+		{name: "capped at max factor", img: textLines(100, 80, 4, 12, 255, 0), target: 40, wantWidth: 400},
+		{name: "text already large", img: textLines(100, 80, 30, 12, 255, 0), target: 24, wantWidth: 100},
+		{name: "no text", img: newGrayFilled(100, 80, 255), target: 24, wantWidth: 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := UpscaleStage(tt.target).Apply(tt.img)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := out.Bounds().Dx(); got != tt.wantWidth {
+				t.Errorf("width = %d, want %d", got, tt.wantWidth)
+			}
+		})
+	}
+}
+
+func TestAutoInvertStage(t *testing.T) {
+	// 어두운 배경은 흰 배경/검은 글자로 반전합니다.
+	dark := textLines(100, 100, 8, 12, 10, 240)
+	out, err := AutoInvertStage().Apply(dark)
+	if err != nil {
+		t.Fatal(err)
+	}
+	inverted := out.(*image.Gray)
+	if got := inverted.GrayAt(0, 0).Y; got != 245 {
+		t.Errorf("inverted background = %d, want 245", got)
+	}
+	if IsDarkBackground(inverted) {
+		t.Error("inverted image still has a dark background")
+	}
+
+	// 밝은 배경은 흑백으로 바꾸지 않고 입력을 그대로 반환합니다.
+	light := image.NewRGBA(image.Rect(0, 0, 20, 20))
+	for i := range light.Pix {
+		light.Pix[i] = 250
+	}
+	light.Set(5, 5, color.RGBA{R: 200, A: 255})
+	out, err = AutoInvertStage().Apply(light)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != image.Image(light) {
+		t.Errorf("AutoInvertStage returned %T, want the input image", out)
+	}
+}
+
+func TestDenoiseStage(t *testing.T) {
+	gray := newGrayFilled(30, 30, 255)
+	for _, p := range []image.Point{{3, 3}, {10, 20}, {25, 7}} {
+		gray.SetGray(p.X, p.Y, color.Gray{})
+	}
+	fillRect(gray, image.Rect(12, 12, 18, 18), 0)
+
+	out, err := DenoiseStage().Apply(gray)
+	if err != nil {
+		t.Fatal(err)
+	}
+	denoised := out.(*image.Gray)
+	for _, p := range []image.Point{{3, 3}, {10, 20}, {25, 7}} {
+		if got := denoised.GrayAt(p.X, p.Y).Y; got != 255 {
+			t.Errorf("speck %v = %d, want removed", p, got)
+		}
+	}
+	if got := denoised.GrayAt(15, 15).Y; got != 0 {
+		t.Errorf("block center = %d, want kept", got)
+	}
+}
@@ -0,0 +1,59 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/png"
+	"testing"
+
+	types "github.com/ndns-dev/ndns-tesseract/src/types"
+)
+
+// encodeTestPNG는 width x height 크기의 회색 PNG를 만듭니다.
+func encodeTestPNG(t *testing.T, width, height int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, width, height))); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestDecodeImage(t *testing.T) {
+	// IHDR의 크기만 60000x60000으로 바꾸고 체크섬을 다시 계산한 PNG입니다. 픽셀 데이터를 디코딩하기 전에 거부되어야 합니다.
+	bomb := encodeTestPNG(t, 4, 4)
+	binary.BigEndian.PutUint32(bomb[16:], 60000)
+	binary.BigEndian.PutUint32(bomb[20:], 60000)
+	binary.BigEndian.PutUint32(bomb[29:], crc32.ChecksumIEEE(bomb[12:29]))
+
+	tests := []struct {
+		name     string
+		data     []byte
+		wantSize image.Point
+		wantCode types.ErrorCode
+	}{
+		{name: "png", data: encodeTestPNG(t, 40, 30), wantSize: image.Pt(40, 30)},
+		{name: "oversized header", data: bomb, wantCode: types.ErrorCodeImageTooLarge},
+		{name: "not an image", data: []byte("not an image"), wantCode: types.ErrorCodeImageDecode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img, format, err := DecodeImage(tt.data)
+			if tt.wantCode != "" {
+				if got := ErrorCodeOf(err); err == nil || got != tt.wantCode {
+					t.Fatalf("error code = %q (%v), want %q", got, err, tt.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if format != "png" || img.Bounds().Size() != tt.wantSize {
+				t.Errorf("DecodeImage() = %s %v, want png %v", format, img.Bounds().Size(), tt.wantSize)
+			}
+		})
+	}
+}
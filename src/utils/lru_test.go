@@ -0,0 +1,71 @@
+package utils
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestLRUCache(t *testing.T) {
+	cache := NewLRUCache[string, int](2)
+	cache.Put("a", 1)
+	cache.Put("b", 2)
+
+	// a를 사용했으므로 다음에 넣는 c는 b를 밀어냅니다.
+	if v, ok := cache.Get("a"); !ok || v != 1 {
+		t.Fatalf("Get(a) = %d, %t", v, ok)
+	}
+	cache.Put("c", 3)
+	if _, ok := cache.Get("b"); ok {
+		t.Error("b should have been evicted")
+	}
+	if v, ok := cache.Get("c"); !ok || v != 3 {
+		t.Errorf("Get(c) = %d, %t", v, ok)
+	}
+
+	// 기존 키를 갱신하면 크기는 그대로이고 최근 사용으로 표시됩니다.
+	cache.Put("a", 10)
+	cache.Put("d", 4)
+	if v, ok := cache.Get("a"); !ok || v != 10 {
+		t.Errorf("Get(a) = %d, %t, want 10", v, ok)
+	}
+	if _, ok := cache.Get("c"); ok {
+		t.Error("c should have been evicted")
+	}
+	if cache.Len() != 2 {
+		t.Errorf("Len = %d, want 2", cache.Len())
+	}
+
+	cache.Remove("a")
+	cache.Remove("missing")
+	if _, ok := cache.Get("a"); ok || cache.Len() != 1 {
+		t.Errorf("after Remove: Len = %d", cache.Len())
+	}
+}
+
+func TestLRUCacheMinimumCapacity(t *testing.T) {
+	cache := NewLRUCache[int, int](0)
+	cache.Put(1, 1)
+	cache.Put(2, 2)
+	if cache.Len() != 1 {
+		t.Errorf("Len = %d, want 1", cache.Len())
+	}
+}
+
+func TestLRUCacheConcurrent(t *testing.T) {
+	cache := NewLRUCache[int, int](16)
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				cache.Put(w*100+i, i)
+				cache.Get(i)
+			}
+		}(w)
+	}
+	wg.Wait()
+	if cache.Len() != 16 {
+		t.Errorf("Len = %d, want 16", cache.Len())
+	}
+}
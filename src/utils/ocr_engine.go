@@ -0,0 +1,92 @@
+package utils
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+
+	types "github.com/ndns-dev/ndns-tesseract/src/types"
+)
+
+// OcrEngine은 이미지 바이트를 받아 텍스트를 인식하는 OCR 엔진입니다.
+type OcrEngine interface {
+	// Name은 엔진 식별자를 반환합니다.
+	Name() string
+	// Version은 결과 버전 관리에 쓰이는 엔진 버전(예: "tesseract 5.3.4")을 반환합니다. 알 수 없으면 빈 문자열입니다.
+	Version(ctx context.Context) string
+	// Recognize는 이미지 바이트를 주어진 옵션으로 인식합니다.
+	Recognize(ctx context.Context, imageBytes []byte, options types.OcrOptions) (*types.OcrEngineResult, error)
+}
+
+// fakeWordConfidence는 FakeOcrEngine이 모든 단어에 부여하는 신뢰도입니다.
+const fakeWordConfidence = 90
+
+// FakeOcrEngine은 테스트용 결정적 OCR 엔진입니다.
+// 이미지의 SHA-256 해시로 결과 텍스트를 찾고, 없으면 DefaultText를 반환합니다.
+type FakeOcrEngine struct {
+	Texts       map[string]string // 이미지 해시(hex) -> 인식 텍스트
+	DefaultText string
+	Err         error
+
+	mu    sync.Mutex
+	calls []types.OcrOptions
+}
+
+// NewFakeOcrEngine은 항상 defaultText를 반환하는 FakeOcrEngine을 생성합니다.
+func NewFakeOcrEngine(defaultText string) *FakeOcrEngine {
+	return &FakeOcrEngine{
+		Texts:       make(map[string]string),
+		DefaultText: defaultText,
+	}
+}
+
+// SetText는 특정 이미지 바이트에 대한 인식 결과를 등록합니다.
+func (e *FakeOcrEngine) SetText(imageBytes []byte, text string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.Texts[HashBytes(imageBytes)] = text
+}
+
+// Calls는 지금까지 Recognize에 전달된 옵션 목록을 반환합니다.
+func (e *FakeOcrEngine) Calls() []types.OcrOptions {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]types.OcrOptions(nil), e.calls...)
+}
+
+func (e *FakeOcrEngine) Name() string {
+	return "fake"
+}
+
+func (e *FakeOcrEngine) Version(ctx context.Context) string {
+	return "fake"
+}
+
+func (e *FakeOcrEngine) Recognize(ctx context.Context, imageBytes []byte, options types.OcrOptions) (*types.OcrEngineResult, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.calls = append(e.calls, options)
+
+	if e.Err != nil {
+		return nil, e.Err
+	}
+
+	text, ok := e.Texts[HashBytes(imageBytes)]
+	if !ok {
+		text = e.DefaultText
+	}
+	lines := BuildLinesFromText(text, fakeWordConfidence)
+	return &types.OcrEngineResult{
+		Engine:         e.Name(),
+		Text:           JoinLineTexts(lines),
+		Lines:          lines,
+		MeanConfidence: MeanWordConfidence(lines),
+	}, nil
+}
+
+// HashBytes는 바이트 배열의 SHA-256 해시를 hex 문자열로 반환합니다.
+func HashBytes(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
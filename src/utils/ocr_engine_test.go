@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	types "github.com/ndns-dev/ndns-tesseract/src/types"
+)
+
+func TestFakeOcrEngine(t *testing.T) {
+	ctx := context.Background()
+	engine := NewFakeOcrEngine("기본 텍스트")
+	registered := []byte("registered image")
+	engine.SetText(registered, "소정의 원고료를\n받아 작성")
+
+	tests := []struct {
+		name     string
+		image    []byte
+		wantText string
+	}{
+		{name: "registered image", image: registered, wantText: "소정의 원고료를 받아 작성"},
+		{name: "unknown image", image: []byte("other"), wantText: "기본 텍스트"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := engine.Recognize(ctx, tt.image, types.OcrOptions{Psm: 6})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Text != tt.wantText {
+				t.Errorf("Text = %q, want %q", result.Text, tt.wantText)
+			}
+			if result.Engine != "fake" || result.MeanConfidence != fakeWordConfidence {
+				t.Errorf("Engine = %q, MeanConfidence = %.2f", result.Engine, result.MeanConfidence)
+			}
+			if len(result.Lines) == 0 {
+				t.Error("expected lines")
+			}
+		})
+	}
+
+	calls := engine.Calls()
+	if len(calls) != len(tests) || calls[0].Psm != 6 {
+		t.Errorf("Calls = %+v", calls)
+	}
+}
+
+func TestFakeOcrEngineError(t *testing.T) {
+	engine := NewFakeOcrEngine("text")
+	engine.Err = errors.New("engine failed")
+	if _, err := engine.Recognize(context.Background(), []byte("image"), types.OcrOptions{}); !errors.Is(err, engine.Err) {
+		t.Errorf("err = %v, want %v", err, engine.Err)
+	}
+	if len(engine.Calls()) != 1 {
+		t.Error("failed calls should still be recorded")
+	}
+}
+
+func TestHashBytes(t *testing.T) {
+	// echo -n "" | sha256sum
+	if got := HashBytes(nil); got != "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855" {
+		t.Errorf("HashBytes(nil) = %s", got)
+	}
+	if HashBytes([]byte("a")) == HashBytes([]byte("b")) {
+		t.Error("different inputs should hash differently")
+	}
+}
@@ -2,72 +2,247 @@ package utils
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"log"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
+
+	types "github.com/ndns-dev/ndns-tesseract/src/types"
 )
 
 const tessdataPath = "/opt/share/tessdata"
 const tesseractCmdPath = "/opt/bin/tesseract"
 
-// PerformOCR 함수는 이제 API Gateway 등으로부터 받은 전체 요청 바디를 받습니다.
-// 요청 바디 안에는 이미지 URL이 JSON 형태로 포함될 것이라고 가정합니다.
-func PerformOCR(imageUrl string) (string, error) {
-	// 1. 이미지 바이트를 메모리로 가져오기
-	log.Printf("Fetching image bytes from URL: %s", imageUrl)
-	imageBytes, err := FetchImageBytes(imageUrl)
-	if err != nil {
-		log.Printf("ERROR: Failed to fetch image bytes from URL %s: %v", imageUrl, err)
-		return "", fmt.Errorf("failed to fetch image: %v", err)
+// DefaultTesseractProcesses는 동시에 실행하는 tesseract 프로세스 수의 기본 상한입니다.
+// SQS 레코드, 타일, 다중 패스 변형의 병렬 처리가 겹쳐도 CPU 수보다 많은 프로세스가 경쟁하지 않게 합니다.
+var DefaultTesseractProcesses = runtime.NumCPU()
+
+// versionTimeout은 `tesseract --version` 실행의 제한 시간입니다.
+const versionTimeout = 5 * time.Second
+
+// versionRetryInterval은 버전 확인에 실패한 뒤 다시 실행하기까지 기다리는 시간입니다.
+// 그동안의 호출은 프로세스를 띄우지 않고 빈 버전을 반환합니다.
+const versionRetryInterval = time.Minute
+
+// TesseractEngine은 Tesseract CLI를 실행하는 OcrEngine 구현체입니다.
+type TesseractEngine struct {
+	CmdPath      string // tesseract 실행 파일 경로
+	TessdataPath string // 언어 데이터 디렉토리
+	UserFilesDir string // 사용자 단어/패턴 파일 디렉토리 (비어 있으면 사용자 파일 옵션을 거부)
+	MaxProcesses int    // 동시에 실행하는 tesseract 프로세스 수 상한 (0이면 제한 없음)
+
+	slotsOnce sync.Once
+	slots     chan struct{}
+
+	versionMu      sync.Mutex
+	version        string        // 한 번 성공하면 재사용하는 버전 문자열
+	versionRetryAt time.Time     // 실패한 버전 확인을 다시 실행할 수 있는 시각
+	versionDone    chan struct{} // 진행 중인 버전 확인이 끝나면 닫힘 (nil이면 진행 중인 확인 없음)
+}
+
+// NewTesseractEngine은 Lambda 이미지 기본 경로를 사용하는 TesseractEngine을 생성합니다.
+func NewTesseractEngine() *TesseractEngine {
+	return &TesseractEngine{
+		CmdPath:      tesseractCmdPath,
+		TessdataPath: tessdataPath,
+		MaxProcesses: DefaultTesseractProcesses,
 	}
-	log.Printf("Image fetched. Size: %d bytes", len(imageBytes))
+}
 
-	// 2. 임시 파일로 저장
-	tempFile, err := os.CreateTemp("", "ocr_image_*.jpg")
-	if err != nil {
-		log.Printf("ERROR: Failed to create temp file: %v", err)
-		return "", fmt.Errorf("failed to create temp file: %v", err)
+// NewTesseractEngineFromEnv는 환경 변수로 경로를 재정의할 수 있는 TesseractEngine을 생성합니다.
+// TESSERACT_CMD_PATH는 실행 파일 경로, TESSDATA_PATH는 언어 데이터 경로입니다.
+// TESSDATA_PATH를 빈 값으로 설정하면 --tessdata-dir 없이 TESSDATA_PREFIX 또는 설치 기본값을 사용합니다.
+// TESSERACT_USER_FILES_DIR은 요청에서 이름으로 지정하는 사용자 단어/패턴 파일의 디렉토리이고,
+// TESSERACT_MAX_PROCESSES는 동시에 실행하는 tesseract 프로세스 수 상한입니다.
+func NewTesseractEngineFromEnv() *TesseractEngine {
+	engine := NewTesseractEngine()
+	if cmdPath := os.Getenv("TESSERACT_CMD_PATH"); cmdPath != "" {
+		engine.CmdPath = cmdPath
+	}
+	if dataPath, ok := os.LookupEnv("TESSDATA_PATH"); ok {
+		engine.TessdataPath = dataPath
+	}
+	engine.UserFilesDir = os.Getenv("TESSERACT_USER_FILES_DIR")
+	if raw := os.Getenv("TESSERACT_MAX_PROCESSES"); raw != "" {
+		if v, err := strconv.Atoi(raw); err == nil && v >= 0 {
+			engine.MaxProcesses = v
+		} else {
+			log.Printf("WARNING: Invalid TESSERACT_MAX_PROCESSES %q, using %d", raw, engine.MaxProcesses)
+		}
 	}
-	defer os.Remove(tempFile.Name())
-	defer tempFile.Close()
+	return engine
+}
 
-	// 이미지 바이트를 임시 파일에 저장
-	_, err = tempFile.Write(imageBytes)
-	if err != nil {
-		log.Printf("ERROR: Failed to write image to temp file: %v", err)
-		return "", fmt.Errorf("failed to write image to temp file: %v", err)
+func (e *TesseractEngine) Name() string {
+	return "tesseract"
+}
+
+// Version은 `tesseract --version` 출력의 첫 줄을 반환합니다. 실행 파일은 바뀌지 않으므로 성공한 결과를 기억하고,
+// 실패하면 versionRetryInterval 동안 빈 문자열을 반환한 뒤 다시 시도합니다.
+// 동시에 호출되면 프로세스는 하나만 실행하고 나머지 호출은 그 결과를 기다립니다. 잠금은 실행 중에 잡고 있지 않습니다.
+func (e *TesseractEngine) Version(ctx context.Context) string {
+	e.versionMu.Lock()
+	if e.version != "" || time.Now().Before(e.versionRetryAt) {
+		version := e.version
+		e.versionMu.Unlock()
+		return version
 	}
-	tempFile.Close()
+	if done := e.versionDone; done != nil {
+		e.versionMu.Unlock()
+		select {
+		case <-done:
+		case <-ctx.Done():
+			return ""
+		}
+		e.versionMu.Lock()
+		defer e.versionMu.Unlock()
+		return e.version
+	}
+	done := make(chan struct{})
+	e.versionDone = done
+	e.versionMu.Unlock()
+
+	version, err := e.lookupVersion()
 
-	// 3. 이미지 최적화 (크롭)
-	log.Printf("Optimizing image for OCR...")
-	optimizedImagePath, err := CropImageOptimal(tempFile.Name())
+	e.versionMu.Lock()
 	if err != nil {
-		log.Printf("WARNING: Failed to optimize image, using original: %v", err)
-		optimizedImagePath = tempFile.Name()
+		log.Printf("WARNING: Failed to get tesseract version, retrying after %s: %v", versionRetryInterval, err)
+		e.versionRetryAt = time.Now().Add(versionRetryInterval)
 	} else {
-		log.Printf("Image optimized successfully: %s", optimizedImagePath)
+		e.version = version
+	}
+	e.versionDone = nil
+	e.versionMu.Unlock()
+	close(done)
+	return version
+}
+
+// lookupVersion은 `tesseract --version`을 실행합니다. 요청 컨텍스트가 취소되어도 버전 확인이
+// 실패하지 않도록 ctx 대신 versionTimeout을 가진 별도 컨텍스트로 실행합니다.
+func (e *TesseractEngine) lookupVersion() (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), versionTimeout)
+	defer cancel()
+	output, err := exec.CommandContext(ctx, e.CmdPath, "--version").CombinedOutput()
+	if err != nil {
+		return "", err
+	}
+	line, _, _ := strings.Cut(strings.TrimSpace(string(output)), "\n")
+	return strings.TrimSpace(line), nil
+}
+
+// buildArgs는 옵션을 Tesseract 명령행 인자로 변환합니다.
+func (e *TesseractEngine) buildArgs(options types.OcrOptions) ([]string, error) {
+	defaults := types.DefaultOcrOptions()
+	if options.Languages == "" {
+		options.Languages = defaults.Languages
 	}
+	if options.Psm == 0 {
+		options.Psm = defaults.Psm
+	}
+	if options.Oem == 0 {
+		options.Oem = defaults.Oem
+	}
+
+	args := []string{"-", "stdout", "-l", options.Languages}
+	if e.TessdataPath != "" {
+		args = append(args, "--tessdata-dir", e.TessdataPath)
+	}
+	args = append(args,
+		"--psm", strconv.Itoa(options.Psm),
+		"--oem", strconv.Itoa(options.Oem))
+
+	if options.UserWords != "" {
+		path, err := e.resolveUserFile(options.UserWords)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, "--user-words", path)
+	}
+	if options.UserPatterns != "" {
+		path, err := e.resolveUserFile(options.UserPatterns)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, "--user-patterns", path)
+	}
+
+	// 인자 순서를 고정하기 위해 변수 이름을 정렬합니다.
+	keys := make([]string, 0, len(options.Variables))
+	for k := range options.Variables {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		args = append(args, "-c", fmt.Sprintf("%s=%s", k, options.Variables[k]))
+	}
+
+	// 단어 단위 좌표와 신뢰도를 얻기 위해 TSV 형식으로 출력합니다.
+	args = append(args, "tsv")
+	return args, nil
+}
 
-	// 4. 최적화된 이미지를 바이트로 다시 읽기
-	optimizedImageBytes, err := os.ReadFile(optimizedImagePath)
+// resolveUserFile은 사용자 파일 이름을 UserFilesDir 안의 경로로 변환합니다.
+// 디렉토리 밖을 가리키거나 존재하지 않는 파일은 거부합니다.
+func (e *TesseractEngine) resolveUserFile(name string) (string, error) {
+	if e.UserFilesDir == "" {
+		return "", Errorf(types.ErrorCodeValidation, "user file %q requested but TESSERACT_USER_FILES_DIR is not configured", name)
+	}
+	if filepath.Base(name) != name {
+		return "", Errorf(types.ErrorCodeValidation, "invalid user file name: %q", name)
+	}
+	path := filepath.Join(e.UserFilesDir, name)
+	if _, err := os.Stat(path); err != nil {
+		return "", Errorf(types.ErrorCodeValidation, "user file not available: %w", err)
+	}
+	return path, nil
+}
+
+// acquireProcess는 tesseract 프로세스 실행 슬롯을 얻고 반납 함수를 반환합니다.
+// 슬롯을 기다리는 동안 ctx가 끝나면 오류를 반환합니다.
+func (e *TesseractEngine) acquireProcess(ctx context.Context) (func(), error) {
+	e.slotsOnce.Do(func() {
+		if e.MaxProcesses > 0 {
+			e.slots = make(chan struct{}, e.MaxProcesses)
+		}
+	})
+	if e.slots == nil {
+		return func() {}, nil
+	}
+	select {
+	case e.slots <- struct{}{}:
+		return func() { <-e.slots }, nil
+	case <-ctx.Done():
+		return nil, fmt.Errorf("tesseract 실행 대기 중 취소됨: %w", ctx.Err())
+	}
+}
+
+// Recognize는 이미지 바이트를 표준 입력으로 Tesseract에 전달해 텍스트를 인식합니다.
+// 동시에 실행하는 프로세스 수는 MaxProcesses로 제한됩니다.
+func (e *TesseractEngine) Recognize(ctx context.Context, imageBytes []byte, options types.OcrOptions) (*types.OcrEngineResult, error) {
+	args, err := e.buildArgs(options)
 	if err != nil {
-		log.Printf("ERROR: Failed to read optimized image: %v", err)
-		return "", fmt.Errorf("failed to read optimized image: %v", err)
+		return nil, err
 	}
 
-	// 5. Tesseract OCR 실행
-	cmd := exec.Command(tesseractCmdPath, "-", "stdout", "-l", "kor", "--tessdata-dir", tessdataPath,
-		"--psm", "6",
-		"--oem", "3",
-		"-c", "preserve_interword_spaces=1")
+	release, err := e.acquireProcess(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	startedAt := time.Now()
 
-	cmd.Stdin = bytes.NewReader(optimizedImageBytes)
-	env := os.Environ()
-	cmd.Env = env
+	cmd := exec.CommandContext(ctx, e.CmdPath, args...)
+	cmd.Stdin = bytes.NewReader(imageBytes)
+	cmd.Env = os.Environ()
 
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
@@ -84,15 +259,23 @@ func PerformOCR(imageUrl string) (string, error) {
 			errMsg = fmt.Sprintf("%s - %s", errMsg, stderrStr)
 		}
 		log.Printf("ERROR: %s", errMsg)
-		return "", errors.New(errMsg)
+		return nil, NewError(types.ErrorCodeOcrEngine, errors.New(errMsg))
 	}
 
-	// OCR 결과에서 줄바꿈 문자를 공백으로 변환
-	cleanedOutput := strings.ReplaceAll(stdout.String(), "\n", " ")
-	cleanedOutput = strings.ReplaceAll(cleanedOutput, "\r", " ")
+	lines, err := ParseTesseractTSV(stdout.String())
+	if err != nil {
+		return nil, Errorf(types.ErrorCodeOcrEngine, "failed to parse Tesseract TSV output: %w", err)
+	}
 
-	OcrResult := strings.TrimSpace(cleanedOutput)
-	log.Printf("Tesseract stdout (cleaned): %s", OcrResult)
+	// 줄 단위 텍스트를 공백으로 이어 한 줄로 만듭니다.
+	text := JoinLineTexts(lines)
+	log.Printf("Tesseract text (from TSV, %d lines): %s", len(lines), text)
 
-	return OcrResult, nil
+	return &types.OcrEngineResult{
+		Engine:         e.Name(),
+		Text:           text,
+		Lines:          lines,
+		MeanConfidence: MeanWordConfidence(lines),
+		Duration:       time.Since(startedAt),
+	}, nil
 }
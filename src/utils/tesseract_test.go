@@ -0,0 +1,161 @@
+package utils
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	types "github.com/ndns-dev/ndns-tesseract/src/types"
+)
+
+// writeScript는 실행 가능한 셸 스크립트를 만듭니다.
+func writeScript(t *testing.T, dir, name, body string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+body), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestTesseractEngineVersion(t *testing.T) {
+	dir := t.TempDir()
+	calls := filepath.Join(dir, "calls")
+	engine := &TesseractEngine{CmdPath: filepath.Join(dir, "missing")}
+
+	// 실패하면 재시도 간격 동안은 실행 파일이 생겨도 다시 실행하지 않습니다.
+	if got := engine.Version(context.Background()); got != "" {
+		t.Fatalf("Version = %q, want empty on failure", got)
+	}
+	engine.CmdPath = writeScript(t, dir, "tesseract",
+		"echo x >> "+calls+"\necho 'tesseract 5.3.4'\necho ' leptonica-1.82.0'\n")
+	if got := engine.Version(context.Background()); got != "" {
+		t.Fatalf("Version = %q within the retry interval, want empty", got)
+	}
+	if _, err := os.Stat(calls); !os.IsNotExist(err) {
+		t.Fatalf("tesseract --version ran within the retry interval")
+	}
+
+	// 재시도 간격이 지나면 다시 실행하고, 요청 컨텍스트가 이미 취소되어도 별도 컨텍스트로 실행됩니다.
+	engine.versionRetryAt = time.Time{}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if got := engine.Version(ctx); got != "tesseract 5.3.4" {
+		t.Fatalf("Version = %q, want %q", got, "tesseract 5.3.4")
+	}
+	if got := engine.Version(context.Background()); got != "tesseract 5.3.4" {
+		t.Fatalf("Version = %q on second call", got)
+	}
+
+	data, err := os.ReadFile(calls)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(string(data), "x"); n != 1 {
+		t.Errorf("tesseract --version ran %d times, want 1", n)
+	}
+}
+
+func TestTesseractEngineVersionConcurrent(t *testing.T) {
+	dir := t.TempDir()
+	calls := filepath.Join(dir, "calls")
+	engine := &TesseractEngine{
+		CmdPath: writeScript(t, dir, "tesseract", "echo x >> "+calls+"\nsleep 0.1\necho 'tesseract 5.3.4'\n"),
+	}
+
+	// 동시에 들어온 호출은 하나의 실행 결과를 함께 기다립니다.
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if got := engine.Version(context.Background()); got != "tesseract 5.3.4" {
+				t.Errorf("Version = %q, want %q", got, "tesseract 5.3.4")
+			}
+		}()
+	}
+	wg.Wait()
+
+	data, err := os.ReadFile(calls)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(string(data), "x"); n != 1 {
+		t.Errorf("tesseract --version ran %d times, want 1", n)
+	}
+}
+
+func TestTesseractEngineMaxProcesses(t *testing.T) {
+	dir := t.TempDir()
+	running := filepath.Join(dir, "running")
+	seen := filepath.Join(dir, "seen")
+	if err := os.Mkdir(running, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	// 실행 중인 프로세스 수를 기록하고 잠시 기다린 뒤 빈 TSV를 출력하는 가짜 tesseract입니다.
+	engine := &TesseractEngine{
+		CmdPath: writeScript(t, dir, "tesseract",
+			"touch "+running+"/$$\nls "+running+" | wc -l >> "+seen+"\nsleep 0.1\nrm "+running+"/$$\n"),
+		MaxProcesses: 2,
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := engine.Recognize(context.Background(), []byte("image"), types.DefaultOcrOptions()); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	data, err := os.ReadFile(seen)
+	if err != nil {
+		t.Fatal(err)
+	}
+	peak := 0
+	for _, field := range strings.Fields(string(data)) {
+		n, _ := strconv.Atoi(field)
+		peak = max(peak, n)
+	}
+	if peak != 2 {
+		t.Errorf("peak concurrent processes = %d, want 2", peak)
+	}
+}
+
+func TestTesseractEngineWaitForProcessSlot(t *testing.T) {
+	engine := &TesseractEngine{MaxProcesses: 1}
+	release, err := engine.acquireProcess(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 슬롯이 모두 사용 중이면 ctx가 끝날 때까지만 기다립니다.
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := engine.acquireProcess(ctx); err == nil {
+		t.Fatal("acquireProcess() succeeded while the only slot was taken")
+	}
+
+	release()
+	again, err := engine.acquireProcess(context.Background())
+	if err != nil {
+		t.Fatalf("acquireProcess() after release: %v", err)
+	}
+	again()
+
+	// 상한이 0이면 제한하지 않습니다.
+	unlimited := &TesseractEngine{}
+	for i := 0; i < 10; i++ {
+		if _, err := unlimited.acquireProcess(ctx); err != nil {
+			t.Fatalf("unlimited acquireProcess() = %v", err)
+		}
+	}
+}
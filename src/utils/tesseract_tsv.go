@@ -0,0 +1,171 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	types "github.com/ndns-dev/ndns-tesseract/src/types"
+)
+
+// Tesseract TSV 출력 컬럼 순서
+const (
+	tsvColLevel = iota
+	tsvColPageNum
+	tsvColBlockNum
+	tsvColParNum
+	tsvColLineNum
+	tsvColWordNum
+	tsvColLeft
+	tsvColTop
+	tsvColWidth
+	tsvColHeight
+	tsvColConf
+	tsvColText
+	tsvColumnCount
+)
+
+// tsvWordLevel은 TSV에서 단어 행을 나타내는 level 값입니다.
+const tsvWordLevel = 5
+
+// ParseTesseractTSV는 Tesseract TSV 출력을 줄/단어 단위 결과로 변환합니다.
+// 빈 단어와 신뢰도가 음수인 행은 제외합니다.
+func ParseTesseractTSV(tsv string) ([]types.OcrLine, error) {
+	var lines []types.OcrLine
+	lineIndex := make(map[string]int)
+
+	for i, row := range strings.Split(tsv, "\n") {
+		row = strings.TrimRight(row, "\r")
+		if row == "" || (i == 0 && strings.HasPrefix(row, "level")) {
+			continue
+		}
+
+		cols := strings.SplitN(row, "\t", tsvColumnCount)
+		if len(cols) < tsvColumnCount-1 {
+			return nil, fmt.Errorf("invalid TSV row %d: expected %d columns, got %d", i, tsvColumnCount, len(cols))
+		}
+
+		level, err := strconv.Atoi(cols[tsvColLevel])
+		if err != nil {
+			return nil, fmt.Errorf("invalid TSV level at row %d: %w", i, err)
+		}
+		if level != tsvWordLevel || len(cols) < tsvColumnCount {
+			continue
+		}
+
+		text := strings.TrimSpace(cols[tsvColText])
+		conf, err := strconv.ParseFloat(cols[tsvColConf], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid TSV confidence at row %d: %w", i, err)
+		}
+		if text == "" || conf < 0 {
+			continue
+		}
+
+		box, err := parseTSVBox(cols)
+		if err != nil {
+			return nil, fmt.Errorf("invalid TSV bounding box at row %d: %w", i, err)
+		}
+
+		word := types.OcrWord{
+			Text:        text,
+			Confidence:  conf,
+			BoundingBox: box,
+		}
+
+		// 같은 page/block/par/line 번호를 가진 단어는 한 줄로 묶습니다.
+		key := strings.Join(cols[tsvColPageNum:tsvColWordNum], ".")
+		idx, ok := lineIndex[key]
+		if !ok {
+			idx = len(lines)
+			lineIndex[key] = idx
+			lines = append(lines, types.OcrLine{})
+		}
+		lines[idx].Words = append(lines[idx].Words, word)
+	}
+
+	for i := range lines {
+		finalizeLine(&lines[i])
+	}
+	return lines, nil
+}
+
+// BuildLinesFromText는 위치 정보가 없는 텍스트를 줄/단어 구조로 변환합니다.
+// 각 단어에는 confidence 값과 순서대로 배치된 가상 좌표가 부여됩니다.
+func BuildLinesFromText(text string, confidence float64) []types.OcrLine {
+	var lines []types.OcrLine
+	for lineNum, rawLine := range strings.Split(text, "\n") {
+		fields := strings.Fields(rawLine)
+		if len(fields) == 0 {
+			continue
+		}
+		line := types.OcrLine{}
+		left := 0
+		for _, field := range fields {
+			width := len([]rune(field)) * 10
+			line.Words = append(line.Words, types.OcrWord{
+				Text:        field,
+				Confidence:  confidence,
+				BoundingBox: types.BoundingBox{Left: left, Top: lineNum * 20, Width: width, Height: 16},
+			})
+			left += width + 10
+		}
+		finalizeLine(&line)
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// JoinLineTexts는 줄 텍스트를 공백으로 이어 한 줄의 텍스트로 만듭니다.
+func JoinLineTexts(lines []types.OcrLine) string {
+	texts := make([]string, 0, len(lines))
+	for _, line := range lines {
+		texts = append(texts, line.Text)
+	}
+	return strings.Join(texts, " ")
+}
+
+// MeanWordConfidence는 모든 단어의 평균 신뢰도를 반환합니다.
+func MeanWordConfidence(lines []types.OcrLine) float64 {
+	var sum float64
+	var count int
+	for _, line := range lines {
+		for _, word := range line.Words {
+			sum += word.Confidence
+			count++
+		}
+	}
+	if count == 0 {
+		return 0
+	}
+	return sum / float64(count)
+}
+
+// finalizeLine은 단어 목록으로부터 줄 텍스트, 영역, 평균 신뢰도를 계산합니다.
+func finalizeLine(line *types.OcrLine) {
+	texts := make([]string, 0, len(line.Words))
+	var box types.BoundingBox
+	var confSum float64
+	for _, word := range line.Words {
+		texts = append(texts, word.Text)
+		box = box.Union(word.BoundingBox)
+		confSum += word.Confidence
+	}
+	line.Text = strings.Join(texts, " ")
+	line.BoundingBox = box
+	if len(line.Words) > 0 {
+		line.Confidence = confSum / float64(len(line.Words))
+	}
+}
+
+func parseTSVBox(cols []string) (types.BoundingBox, error) {
+	values := make([]int, 4)
+	for i, col := range cols[tsvColLeft : tsvColHeight+1] {
+		v, err := strconv.Atoi(col)
+		if err != nil {
+			return types.BoundingBox{}, err
+		}
+		values[i] = v
+	}
+	return types.BoundingBox{Left: values[0], Top: values[1], Width: values[2], Height: values[3]}, nil
+}
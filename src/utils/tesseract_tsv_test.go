@@ -0,0 +1,150 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	types "github.com/ndns-dev/ndns-tesseract/src/types"
+)
+
+// tsvRows는 탭으로 구분한 TSV 행을 헤더와 함께 이어 붙입니다.
+func tsvRows(rows ...string) string {
+	header := "level\tpage_num\tblock_num\tpar_num\tline_num\tword_num\tleft\ttop\twidth\theight\tconf\ttext"
+	return header + "\n" + strings.Join(rows, "\n") + "\n"
+}
+
+func TestParseTesseractTSV(t *testing.T) {
+	tests := []struct {
+		name      string
+		tsv       string
+		wantLines []types.OcrLine
+		wantErr   bool
+	}{
+		{
+			name:      "empty output",
+			tsv:       tsvRows(),
+			wantLines: nil,
+		},
+		{
+			name: "groups words by line and skips non-word levels",
+			tsv: tsvRows(
+				"1\t1\t0\t0\t0\t0\t0\t0\t800\t600\t-1\t",
+				"4\t1\t1\t1\t1\t0\t10\t20\t200\t30\t-1\t",
+				"5\t1\t1\t1\t1\t1\t10\t20\t80\t30\t90.5\t소정의",
+				"5\t1\t1\t1\t1\t2\t100\t22\t110\t28\t79.5\t원고료를",
+				"5\t1\t1\t1\t2\t1\t10\t60\t50\t30\t60\t받아",
+			),
+			wantLines: []types.OcrLine{
+				{
+					Text:        "소정의 원고료를",
+					Confidence:  85,
+					BoundingBox: types.BoundingBox{Left: 10, Top: 20, Width: 200, Height: 30},
+					Words: []types.OcrWord{
+						{Text: "소정의", Confidence: 90.5, BoundingBox: types.BoundingBox{Left: 10, Top: 20, Width: 80, Height: 30}},
+						{Text: "원고료를", Confidence: 79.5, BoundingBox: types.BoundingBox{Left: 100, Top: 22, Width: 110, Height: 28}},
+					},
+				},
+				{
+					Text:        "받아",
+					Confidence:  60,
+					BoundingBox: types.BoundingBox{Left: 10, Top: 60, Width: 50, Height: 30},
+					Words: []types.OcrWord{
+						{Text: "받아", Confidence: 60, BoundingBox: types.BoundingBox{Left: 10, Top: 60, Width: 50, Height: 30}},
+					},
+				},
+			},
+		},
+		{
+			name: "drops blank words and negative confidence",
+			tsv: tsvRows(
+				"5\t1\t1\t1\t1\t1\t10\t20\t80\t30\t90\t   ",
+				"5\t1\t1\t1\t1\t2\t100\t20\t80\t30\t-1\tghost",
+				"5\t1\t1\t1\t1\t3\t200\t20\t80\t30\t70\t협찬\r",
+			),
+			wantLines: []types.OcrLine{
+				{
+					Text:        "협찬",
+					Confidence:  70,
+					BoundingBox: types.BoundingBox{Left: 200, Top: 20, Width: 80, Height: 30},
+					Words: []types.OcrWord{
+						{Text: "협찬", Confidence: 70, BoundingBox: types.BoundingBox{Left: 200, Top: 20, Width: 80, Height: 30}},
+					},
+				},
+			},
+		},
+		{
+			name: "word row without text column is skipped",
+			tsv: tsvRows(
+				"5\t1\t1\t1\t1\t1\t10\t20\t80\t30\t90",
+			),
+			wantLines: nil,
+		},
+		{
+			name:    "too few columns",
+			tsv:     tsvRows("5\t1\t1"),
+			wantErr: true,
+		},
+		{
+			name:    "invalid confidence",
+			tsv:     tsvRows("5\t1\t1\t1\t1\t1\t10\t20\t80\t30\thigh\t협찬"),
+			wantErr: true,
+		},
+		{
+			name:    "invalid box",
+			tsv:     tsvRows("5\t1\t1\t1\t1\t1\tleft\t20\t80\t30\t90\t협찬"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines, err := ParseTesseractTSV(tt.tsv)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %+v", lines)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(lines) != len(tt.wantLines) {
+				t.Fatalf("got %d lines, want %d: %+v", len(lines), len(tt.wantLines), lines)
+			}
+			for i, want := range tt.wantLines {
+				got := lines[i]
+				if got.Text != want.Text || got.Confidence != want.Confidence || got.BoundingBox != want.BoundingBox {
+					t.Errorf("line %d = %q %.2f %+v, want %q %.2f %+v",
+						i, got.Text, got.Confidence, got.BoundingBox, want.Text, want.Confidence, want.BoundingBox)
+				}
+				if len(got.Words) != len(want.Words) {
+					t.Fatalf("line %d has %d words, want %d", i, len(got.Words), len(want.Words))
+				}
+				for j := range want.Words {
+					if got.Words[j] != want.Words[j] {
+						t.Errorf("line %d word %d = %+v, want %+v", i, j, got.Words[j], want.Words[j])
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestBuildLinesFromText(t *testing.T) {
+	lines := BuildLinesFromText("소정의 원고료\n\n협찬", 80)
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2", len(lines))
+	}
+	if got := JoinLineTexts(lines); got != "소정의 원고료 협찬" {
+		t.Errorf("JoinLineTexts = %q", got)
+	}
+	if got := MeanWordConfidence(lines); got != 80 {
+		t.Errorf("MeanWordConfidence = %.2f, want 80", got)
+	}
+	if lines[1].BoundingBox.Top <= lines[0].BoundingBox.Top {
+		t.Errorf("second line %+v is not below first line %+v", lines[1].BoundingBox, lines[0].BoundingBox)
+	}
+	if MeanWordConfidence(nil) != 0 {
+		t.Error("MeanWordConfidence(nil) should be 0")
+	}
+}
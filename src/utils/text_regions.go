@@ -0,0 +1,282 @@
+package utils
+
+import (
+	"image"
+	"math"
+	"sort"
+)
+
+// 텍스트 영역 탐지 기본 설정
+const (
+	DefaultRegionAnalysisSide = 800  // 분석용 축소 이미지의 긴 변 (픽셀)
+	DefaultMinRegionHeight    = 8    // 분석 이미지 기준 최소 영역 높이
+	DefaultMinRegionAspect    = 1.2  // 최소 가로/세로 비율 (글자 줄은 가로로 깁니다)
+	DefaultMinEdgeDensity     = 0.08 // 영역 안 에지 픽셀 최소 비율
+	DefaultMaxTextRegions     = 16
+	regionPaddingRatio        = 0.4 // 영역 높이 대비 여백 비율
+)
+
+// TextRegionDetector는 에지 밀도와 연결 요소 분석으로 글자가 있을 법한 영역을 찾습니다.
+// 세로 획이 촘촘한 곳의 에지를 가로로 이어 붙여 글자 줄 덩어리를 만들고,
+// 크기, 비율, 에지 밀도로 사진의 질감 영역을 걸러냅니다. OpenCV 없이 순수 Go로 동작합니다.
+type TextRegionDetector struct {
+	AnalysisSide    int     // 분석 전에 긴 변을 이 크기로 축소 (0이면 축소하지 않음)
+	MinRegionHeight int     // 분석 이미지 기준 최소 영역 높이
+	MinAspect       float64 // 최소 가로/세로 비율
+	MinEdgeDensity  float64 // 영역 안 에지 픽셀 최소 비율
+	MaxRegions      int     // 반환할 최대 영역 수 (에지가 많은 영역 우선)
+}
+
+// NewTextRegionDetector는 기본 설정으로 탐지기를 생성합니다.
+func NewTextRegionDetector() *TextRegionDetector {
+	return &TextRegionDetector{
+		AnalysisSide:    DefaultRegionAnalysisSide,
+		MinRegionHeight: DefaultMinRegionHeight,
+		MinAspect:       DefaultMinRegionAspect,
+		MinEdgeDensity:  DefaultMinEdgeDensity,
+		MaxRegions:      DefaultMaxTextRegions,
+	}
+}
+
+// textRegionCandidate는 분석 이미지 좌표의 후보 영역과 에지 수입니다.
+type textRegionCandidate struct {
+	rect  image.Rectangle
+	edges int
+}
+
+// Detect는 원본 이미지 좌표계의 텍스트 후보 영역을 위에서 아래, 왼쪽에서 오른쪽 순으로 반환합니다.
+// 후보가 없으면 nil을 반환합니다.
+func (d *TextRegionDetector) Detect(img image.Image) []image.Rectangle {
+	bounds := img.Bounds()
+	if bounds.Empty() {
+		return nil
+	}
+
+	gray, scale := d.analysisImage(img)
+	edges := edgeMap(gray)
+	w, h := gray.Bounds().Dx(), gray.Bounds().Dy()
+
+	// 글자 사이 간격을 메우도록 가로로 길게, 줄 사이는 붙지 않도록 세로로 짧게 팽창합니다.
+	dilateX := max(3, w/60)
+	mask := dilate(edges, w, h, dilateX, 1)
+	edgeSums := integralCounts(edges, w, h)
+
+	var candidates []textRegionCandidate
+	for _, rect := range connectedComponents(mask, w, h) {
+		if rect.Dy() < d.MinRegionHeight || rect.Dy() > h/2 {
+			continue
+		}
+		if float64(rect.Dx())/float64(rect.Dy()) < d.MinAspect {
+			continue
+		}
+		count := rectCount(edgeSums, w, rect)
+		if float64(count)/float64(rect.Dx()*rect.Dy()) < d.MinEdgeDensity {
+			continue
+		}
+		candidates = append(candidates, textRegionCandidate{rect: rect, edges: count})
+	}
+	candidates = mergeCandidates(candidates, d.MinRegionHeight/2)
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	if d.MaxRegions > 0 && len(candidates) > d.MaxRegions {
+		sort.SliceStable(candidates, func(a, b int) bool { return candidates[a].edges > candidates[b].edges })
+		candidates = candidates[:d.MaxRegions]
+	}
+
+	regions := make([]image.Rectangle, 0, len(candidates))
+	for _, c := range candidates {
+		pad := max(2, int(float64(c.rect.Dy())*regionPaddingRatio))
+		r := c.rect.Inset(-pad)
+		regions = append(regions, image.Rect(
+			bounds.Min.X+int(float64(r.Min.X)/scale),
+			bounds.Min.Y+int(float64(r.Min.Y)/scale),
+			bounds.Min.X+int(math.Ceil(float64(r.Max.X)/scale)),
+			bounds.Min.Y+int(math.Ceil(float64(r.Max.Y)/scale)),
+		).Intersect(bounds))
+	}
+	// 여백을 더한 뒤 겹치게 된 영역은 같은 줄이 두 번 인식되지 않도록 합칩니다.
+	regions = mergeOverlappingRects(regions)
+	sort.SliceStable(regions, func(a, b int) bool {
+		if regions[a].Min.Y != regions[b].Min.Y {
+			return regions[a].Min.Y < regions[b].Min.Y
+		}
+		return regions[a].Min.X < regions[b].Min.X
+	})
+	return regions
+}
+
+// RegionTiles는 텍스트 영역을 StitchTileLines로 합칠 수 있는 타일로 만듭니다.
+// Detect가 반환하는 영역은 서로 겹치지 않으므로 담당 영역은 타일 영역 전체입니다.
+// 엔진이 반환한 영역 기준 좌표는 MapTileLines가 영역 위치만큼 옮겨 원본 좌표로 바꿉니다.
+func RegionTiles(img image.Image, regions []image.Rectangle) []ImageTile {
+	tiles := make([]ImageTile, len(regions))
+	for i, rect := range regions {
+		tiles[i] = ImageTile{Row: i, Rect: rect, Core: rect, Image: CropImage(img, rect)}
+	}
+	return tiles
+}
+
+// analysisImage는 긴 변이 AnalysisSide 이하가 되도록 축소한 0 기준 흑백 이미지와 배율을 반환합니다.
+func (d *TextRegionDetector) analysisImage(img image.Image) (*image.Gray, float64) {
+	bounds := img.Bounds()
+	scale := 1.0
+	if longest := max(bounds.Dx(), bounds.Dy()); d.AnalysisSide > 0 && longest > d.AnalysisSide {
+		scale = float64(d.AnalysisSide) / float64(longest)
+	}
+	width := max(1, int(float64(bounds.Dx())*scale))
+	height := max(1, int(float64(bounds.Dy())*scale))
+
+	gray := toGray(img)
+	out := image.NewGray(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		sy := bounds.Min.Y + min(bounds.Dy()-1, int(float64(y)/scale))
+		for x := 0; x < width; x++ {
+			sx := bounds.Min.X + min(bounds.Dx()-1, int(float64(x)/scale))
+			out.Pix[y*out.Stride+x] = gray.Pix[gray.PixOffset(sx, sy)]
+		}
+	}
+	return out, scale
+}
+
+// edgeMap은 수평 방향 밝기 변화(세로 획)의 크기를 Otsu 임계값으로 이진화한 에지 맵을 만듭니다.
+func edgeMap(gray *image.Gray) []bool {
+	w, h := gray.Bounds().Dx(), gray.Bounds().Dy()
+	magnitude := image.NewGray(image.Rect(0, 0, w, h))
+	for y := 1; y < h-1; y++ {
+		for x := 1; x < w-1; x++ {
+			at := func(dx, dy int) int { return int(gray.Pix[(y+dy)*gray.Stride+x+dx]) }
+			gx := (at(1, -1) + 2*at(1, 0) + at(1, 1)) - (at(-1, -1) + 2*at(-1, 0) + at(-1, 1))
+			magnitude.Pix[y*magnitude.Stride+x] = uint8(min(255, abs(gx)/4))
+		}
+	}
+
+	// 평탄한 배경이 대부분이면 Otsu 임계값이 너무 낮아지므로 최소값을 둡니다.
+	threshold := max(OtsuThreshold(magnitude), 24)
+	edges := make([]bool, w*h)
+	for i, v := range magnitude.Pix {
+		edges[i] = v > threshold
+	}
+	return edges
+}
+
+// dilate는 이진 맵을 (2*rx+1)x(2*ry+1) 사각형으로 팽창합니다.
+func dilate(mask []bool, w, h, rx, ry int) []bool {
+	sums := integralCounts(mask, w, h)
+	out := make([]bool, w*h)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			rect := image.Rect(max(0, x-rx), max(0, y-ry), min(w, x+rx+1), min(h, y+ry+1))
+			out[y*w+x] = rectCount(sums, w, rect) > 0
+		}
+	}
+	return out
+}
+
+// integralCounts는 true 픽셀 수의 적분 이미지를 (w+1)x(h+1) 크기로 만듭니다.
+func integralCounts(mask []bool, w, h int) []int {
+	sums := make([]int, (w+1)*(h+1))
+	for y := 0; y < h; y++ {
+		rowSum := 0
+		for x := 0; x < w; x++ {
+			if mask[y*w+x] {
+				rowSum++
+			}
+			sums[(y+1)*(w+1)+x+1] = sums[y*(w+1)+x+1] + rowSum
+		}
+	}
+	return sums
+}
+
+// rectCount는 적분 이미지로 rect 안의 true 픽셀 수를 계산합니다.
+func rectCount(sums []int, w int, rect image.Rectangle) int {
+	stride := w + 1
+	return sums[rect.Max.Y*stride+rect.Max.X] - sums[rect.Min.Y*stride+rect.Max.X] -
+		sums[rect.Max.Y*stride+rect.Min.X] + sums[rect.Min.Y*stride+rect.Min.X]
+}
+
+// connectedComponents는 4-연결 요소의 경계 사각형을 반환합니다.
+func connectedComponents(mask []bool, w, h int) []image.Rectangle {
+	visited := make([]bool, len(mask))
+	var rects []image.Rectangle
+	var stack []int
+	for start, on := range mask {
+		if !on || visited[start] {
+			continue
+		}
+		visited[start] = true
+		stack = append(stack[:0], start)
+		rect := image.Rect(start%w, start/w, start%w+1, start/w+1)
+		for len(stack) > 0 {
+			i := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			x, y := i%w, i/w
+			rect = rect.Union(image.Rect(x, y, x+1, y+1))
+			for _, n := range [4]int{i - 1, i + 1, i - w, i + w} {
+				if n < 0 || n >= len(mask) || visited[n] || !mask[n] {
+					continue
+				}
+				// 좌우 이웃이 다른 행으로 넘어가지 않도록 합니다.
+				if (n == i-1 && x == 0) || (n == i+1 && x == w-1) {
+					continue
+				}
+				visited[n] = true
+				stack = append(stack, n)
+			}
+		}
+		rects = append(rects, rect)
+	}
+	return rects
+}
+
+// mergeCandidates는 가깝거나 겹치는 후보를 더 이상 합칠 것이 없을 때까지 합칩니다.
+// 가로로는 줄 높이만큼(단어 간격), 세로로는 gap 픽셀 이내면 같은 영역으로 봅니다.
+func mergeCandidates(candidates []textRegionCandidate, gap int) []textRegionCandidate {
+	near := func(a, b image.Rectangle) bool {
+		dx := max(a.Dy(), b.Dy())
+		expanded := image.Rect(a.Min.X-dx, a.Min.Y-gap, a.Max.X+dx, a.Max.Y+gap)
+		return expanded.Overlaps(b)
+	}
+	for merged := true; merged; {
+		merged = false
+		for i := 0; i < len(candidates) && !merged; i++ {
+			for j := i + 1; j < len(candidates); j++ {
+				if !near(candidates[i].rect, candidates[j].rect) {
+					continue
+				}
+				candidates[i].rect = candidates[i].rect.Union(candidates[j].rect)
+				candidates[i].edges += candidates[j].edges
+				candidates = append(candidates[:j], candidates[j+1:]...)
+				merged = true
+				break
+			}
+		}
+	}
+	return candidates
+}
+
+// mergeOverlappingRects는 겹치는 사각형이 없어질 때까지 합칩니다.
+func mergeOverlappingRects(rects []image.Rectangle) []image.Rectangle {
+	for merged := true; merged; {
+		merged = false
+		for i := 0; i < len(rects) && !merged; i++ {
+			for j := i + 1; j < len(rects); j++ {
+				if rects[i].Overlaps(rects[j]) {
+					rects[i] = rects[i].Union(rects[j])
+					rects = append(rects[:j], rects[j+1:]...)
+					merged = true
+					break
+				}
+			}
+		}
+	}
+	return rects
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
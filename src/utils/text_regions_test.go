@@ -0,0 +1,66 @@
+package utils
+
+import (
+	"image"
+	"testing"
+)
+
+// drawGlyphRow는 rect 안에 폭 3픽셀의 세로 획을 3픽셀 간격으로 그려 글자 줄을 흉내 냅니다.
+func drawGlyphRow(gray *image.Gray, rect image.Rectangle) {
+	for x := rect.Min.X; x+3 <= rect.Max.X; x += 6 {
+		fillRect(gray, image.Rect(x, rect.Min.Y, x+3, rect.Max.Y), 0)
+	}
+}
+
+// assertRegions는 regions가 lines와 같은 순서로 하나씩 대응하고, 각 영역이 줄을 모두 포함하면서
+// 사방으로 maxMargin 픽셀보다 넓지 않은지 확인합니다.
+func assertRegions(t *testing.T, regions, lines []image.Rectangle, maxMargin int) {
+	t.Helper()
+	if len(regions) != len(lines) {
+		t.Fatalf("Detect() = %v, want %d regions around %v", regions, len(lines), lines)
+	}
+	for i, line := range lines {
+		if !line.In(regions[i]) {
+			t.Errorf("region %v does not contain text line %v", regions[i], line)
+		}
+		if !regions[i].In(line.Inset(-maxMargin)) {
+			t.Errorf("region %v is more than %dpx larger than text line %v", regions[i], maxMargin, line)
+		}
+	}
+}
+
+func TestTextRegionDetectorDetect(t *testing.T) {
+	lineA := image.Rect(50, 60, 350, 76)
+	lineB := image.Rect(100, 250, 500, 266)
+	img := newGrayFilled(600, 400, 255)
+	drawGlyphRow(img, lineB)
+	drawGlyphRow(img, lineA)
+	// 질감이 없는 큰 사각형은 글자 영역이 아닙니다.
+	fillRect(img, image.Rect(420, 40, 560, 180), 0)
+
+	detector := NewTextRegionDetector()
+	assertRegions(t, detector.Detect(img), []image.Rectangle{lineA, lineB}, 20)
+
+	// 원점이 0이 아닌 이미지도 원본 좌표로 반환합니다.
+	sub := img.SubImage(image.Rect(0, 200, 600, 400))
+	assertRegions(t, detector.Detect(sub), []image.Rectangle{lineB}, 20)
+}
+
+func TestTextRegionDetectorNoText(t *testing.T) {
+	tests := []struct {
+		name string
+		img  image.Image
+	}{
+		{name: "empty image", img: image.NewGray(image.Rect(0, 0, 0, 0))},
+		{name: "blank white", img: newGrayFilled(300, 200, 255)},
+		{name: "full black", img: newGrayFilled(300, 200, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if regions := NewTextRegionDetector().Detect(tt.img); regions != nil {
+				t.Errorf("Detect() = %v, want nil", regions)
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package utils
+
+import (
+	"image"
+	"log"
+	"math"
+	"sort"
+
+	types "github.com/ndns-dev/ndns-tesseract/src/types"
+)
+
+// ImageTile은 큰 이미지를 나눈 타일 하나입니다.
+// Rect는 원본 좌표계의 타일 영역이고, Core는 이웃 타일과 겹치는 구간을 반씩 나눠 가진 이 타일의 담당 영역입니다.
+// 겹치는 구간의 줄은 중심이 Core 안에 있는 타일에서만 사용해 중복을 제거합니다.
+type ImageTile struct {
+	Row   int
+	Col   int
+	Rect  image.Rectangle
+	Core  image.Rectangle
+	Image image.Image
+}
+
+// NeedsTiling은 이미지의 가로나 세로가 maxDimension을 넘는지 확인합니다.
+func NeedsTiling(img image.Image, maxDimension int) bool {
+	bounds := img.Bounds()
+	return bounds.Dx() > maxDimension || bounds.Dy() > maxDimension
+}
+
+// TileImage는 이미지를 한 변이 maxDimension 이하인 타일로 나눕니다. 이웃 타일은 overlap 픽셀 이상 겹칩니다.
+// 타일은 행 우선 순서로 반환됩니다. 타일 수가 maxTiles를 넘으면 협찬 고지가 주로 있는
+// 앞쪽과 뒤쪽 타일을 절반씩 남깁니다.
+func TileImage(img image.Image, maxDimension, overlap, maxTiles int) []ImageTile {
+	bounds := img.Bounds()
+	rows := tileSpans(bounds.Min.Y, bounds.Max.Y, maxDimension, overlap)
+	cols := tileSpans(bounds.Min.X, bounds.Max.X, maxDimension, overlap)
+
+	tiles := make([]ImageTile, 0, len(rows)*len(cols))
+	for r, row := range rows {
+		for c, col := range cols {
+			rect := image.Rect(col.start, row.start, col.end, row.end)
+			tiles = append(tiles, ImageTile{
+				Row:   r,
+				Col:   c,
+				Rect:  rect,
+				Core:  image.Rect(col.coreStart, row.coreStart, col.coreEnd, row.coreEnd),
+				Image: CropImage(img, rect),
+			})
+		}
+	}
+
+	if maxTiles > 0 && len(tiles) > maxTiles {
+		log.Printf("WARNING: Image %dx%d needs %d tiles, keeping first and last %d",
+			bounds.Dx(), bounds.Dy(), len(tiles), maxTiles)
+		head := (maxTiles + 1) / 2
+		tail := maxTiles - head
+		tiles = append(tiles[:head:head], tiles[len(tiles)-tail:]...)
+	}
+	return tiles
+}
+
+// tileSpan은 한 축에서 타일 하나가 차지하는 구간과 담당 구간입니다.
+type tileSpan struct {
+	start, end         int
+	coreStart, coreEnd int
+}
+
+// tileSpans는 [min, max) 구간을 size 이하, overlap 이상 겹치는 구간으로 고르게 나눕니다.
+func tileSpans(minPos, maxPos, size, overlap int) []tileSpan {
+	length := maxPos - minPos
+	if length <= size {
+		return []tileSpan{{start: minPos, end: maxPos, coreStart: minPos, coreEnd: maxPos}}
+	}
+
+	overlap = min(overlap, size/2)
+	count := int(math.Ceil(float64(length-overlap) / float64(size-overlap)))
+	spans := make([]tileSpan, count)
+	for i := range spans {
+		start := minPos + int(math.Round(float64(i)*float64(length-size)/float64(count-1)))
+		spans[i] = tileSpan{start: start, end: start + size}
+	}
+	for i := range spans {
+		spans[i].coreStart = minPos
+		if i > 0 {
+			spans[i].coreStart = (spans[i].start + spans[i-1].end) / 2
+		}
+		spans[i].coreEnd = maxPos
+		if i < len(spans)-1 {
+			spans[i].coreEnd = (spans[i+1].start + spans[i].end) / 2
+		}
+	}
+	return spans
+}
+
+// MapTileLines는 타일 이미지(전처리로 확대되었을 수 있음) 기준의 줄 좌표를 원본 이미지 좌표로 변환합니다.
+// processed는 전처리 후 타일 이미지의 영역입니다. SubImage로 자른 타일은 원점이 타일 위치로 남아 있지만
+// PNG로 인코딩해 엔진에 넘긴 이미지의 좌표는 항상 0부터 시작하므로 processed는 크기만 사용합니다.
+func MapTileLines(tile ImageTile, processed image.Rectangle, lines []types.OcrLine) []types.OcrLine {
+	scaleX, scaleY := 1.0, 1.0
+	if processed.Dx() > 0 {
+		scaleX = float64(tile.Rect.Dx()) / float64(processed.Dx())
+	}
+	if processed.Dy() > 0 {
+		scaleY = float64(tile.Rect.Dy()) / float64(processed.Dy())
+	}
+	mapBox := func(box types.BoundingBox) types.BoundingBox {
+		return types.BoundingBox{
+			Left:   tile.Rect.Min.X + int(math.Round(float64(box.Left)*scaleX)),
+			Top:    tile.Rect.Min.Y + int(math.Round(float64(box.Top)*scaleY)),
+			Width:  int(math.Round(float64(box.Width) * scaleX)),
+			Height: int(math.Round(float64(box.Height) * scaleY)),
+		}
+	}
+
+	mapped := make([]types.OcrLine, len(lines))
+	for i, line := range lines {
+		mapped[i] = line
+		mapped[i].BoundingBox = mapBox(line.BoundingBox)
+		mapped[i].Words = make([]types.OcrWord, len(line.Words))
+		for j, word := range line.Words {
+			mapped[i].Words[j] = word
+			mapped[i].Words[j].BoundingBox = mapBox(word.BoundingBox)
+		}
+	}
+	return mapped
+}
+
+// StitchTileLines는 원본 좌표로 변환된 타일별 줄을 하나로 합칩니다.
+// 겹치는 구간에서 두 타일에 모두 나온 줄은 중심이 담당 영역(Core) 안에 있는 타일의 것만 남기고,
+// 결과는 타일 행, 줄 위치 순으로 정렬합니다.
+func StitchTileLines(tiles []ImageTile, tileLines [][]types.OcrLine) []types.OcrLine {
+	type placedLine struct {
+		row  int
+		line types.OcrLine
+	}
+
+	var placed []placedLine
+	for i, tile := range tiles {
+		for _, line := range tileLines[i] {
+			box := line.BoundingBox
+			center := image.Pt(box.Left+box.Width/2, box.Top+box.Height/2)
+			if box.Width > 0 && box.Height > 0 && !center.In(tile.Core) {
+				continue
+			}
+			placed = append(placed, placedLine{row: tile.Row, line: line})
+		}
+	}
+
+	sort.SliceStable(placed, func(a, b int) bool {
+		if placed[a].row != placed[b].row {
+			return placed[a].row < placed[b].row
+		}
+		return placed[a].line.BoundingBox.Top < placed[b].line.BoundingBox.Top
+	})
+
+	lines := make([]types.OcrLine, len(placed))
+	for i, p := range placed {
+		lines[i] = p.line
+	}
+	return lines
+}
@@ -0,0 +1,179 @@
+package utils
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	types "github.com/ndns-dev/ndns-tesseract/src/types"
+)
+
+func TestTileSpans(t *testing.T) {
+	tests := []struct {
+		name          string
+		min, max      int
+		size, overlap int
+		wantCount     int
+	}{
+		{name: "fits in one tile", min: 0, max: 800, size: 1200, overlap: 100, wantCount: 1},
+		{name: "exact size", min: 0, max: 1200, size: 1200, overlap: 100, wantCount: 1},
+		{name: "tall image", min: 0, max: 3000, size: 1200, overlap: 100, wantCount: 3},
+		{name: "non-zero origin", min: 500, max: 3500, size: 1200, overlap: 100, wantCount: 3},
+		{name: "overlap larger than half", min: 0, max: 2500, size: 1000, overlap: 900, wantCount: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spans := tileSpans(tt.min, tt.max, tt.size, tt.overlap)
+			if len(spans) != tt.wantCount {
+				t.Fatalf("got %d spans, want %d: %+v", len(spans), tt.wantCount, spans)
+			}
+			if spans[0].start != tt.min || spans[len(spans)-1].end != tt.max {
+				t.Errorf("spans %+v do not cover [%d, %d)", spans, tt.min, tt.max)
+			}
+			if spans[0].coreStart != tt.min || spans[len(spans)-1].coreEnd != tt.max {
+				t.Errorf("cores %+v do not cover [%d, %d)", spans, tt.min, tt.max)
+			}
+			for i, span := range spans {
+				if span.end-span.start > tt.size {
+					t.Errorf("span %d is %d long, want at most %d", i, span.end-span.start, tt.size)
+				}
+				if span.coreStart < span.start || span.coreEnd > span.end {
+					t.Errorf("span %d core [%d, %d) is outside [%d, %d)", i, span.coreStart, span.coreEnd, span.start, span.end)
+				}
+				if i == 0 {
+					continue
+				}
+				if overlap := spans[i-1].end - span.start; overlap < min(tt.overlap, tt.size/2) {
+					t.Errorf("spans %d and %d overlap by %d, want at least %d", i-1, i, overlap, min(tt.overlap, tt.size/2))
+				}
+				if span.coreStart != spans[i-1].coreEnd {
+					t.Errorf("cores %d and %d are not adjacent: %d != %d", i-1, i, spans[i-1].coreEnd, span.coreStart)
+				}
+			}
+		})
+	}
+}
+
+// recognizeTileLines는 엔진처럼 타일 안에 완전히 들어오는 줄을 0 기준, factor배 확대된 좌표로 반환합니다.
+func recognizeTileLines(tile ImageTile, lines []types.BoundingBox, factor int) []types.OcrLine {
+	var found []types.OcrLine
+	for _, box := range lines {
+		rect := image.Rect(box.Left, box.Top, box.Left+box.Width, box.Top+box.Height)
+		if !rect.In(tile.Rect) {
+			continue
+		}
+		local := types.BoundingBox{
+			Left:   (box.Left - tile.Rect.Min.X) * factor,
+			Top:    (box.Top - tile.Rect.Min.Y) * factor,
+			Width:  box.Width * factor,
+			Height: box.Height * factor,
+		}
+		found = append(found, types.OcrLine{
+			Text:        "line",
+			BoundingBox: local,
+			Words:       []types.OcrWord{{Text: "line", BoundingBox: local}},
+		})
+	}
+	return found
+}
+
+func TestTiledLinesMapToOriginalCoordinates(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 800, 3000))
+	for i := range img.Pix {
+		img.Pix[i] = 255
+	}
+	var lines []types.BoundingBox
+	for top := 100; top < 2900; top += 250 {
+		lines = append(lines, types.BoundingBox{Left: 40, Top: top, Width: 300, Height: 16})
+	}
+
+	tests := []struct {
+		name      string
+		factor    int
+		processed func(tile ImageTile) image.Rectangle
+	}{
+		{
+			name:   "grayscale keeps sub-image origin",
+			factor: 1,
+			processed: func(tile ImageTile) image.Rectangle {
+				processed, err := NewImagePipeline(GrayscaleStage()).Apply(tile.Image)
+				if err != nil {
+					t.Fatal(err)
+				}
+				return processed.Bounds()
+			},
+		},
+		{
+			name:   "upscaled 2x",
+			factor: 2,
+			processed: func(tile ImageTile) image.Rectangle {
+				return image.Rect(0, 0, tile.Rect.Dx()*2, tile.Rect.Dy()*2)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tiles := TileImage(img, 1200, 100, 0)
+			if len(tiles) != 3 {
+				t.Fatalf("got %d tiles, want 3", len(tiles))
+			}
+
+			tileLines := make([][]types.OcrLine, len(tiles))
+			for i, tile := range tiles {
+				tileLines[i] = MapTileLines(tile, tt.processed(tile), recognizeTileLines(tile, lines, tt.factor))
+			}
+			stitched := StitchTileLines(tiles, tileLines)
+
+			if len(stitched) != len(lines) {
+				t.Fatalf("got %d lines, want %d: %+v", len(stitched), len(lines), stitched)
+			}
+			for i, line := range stitched {
+				if line.BoundingBox != lines[i] {
+					t.Errorf("line %d box = %+v, want %+v", i, line.BoundingBox, lines[i])
+				}
+				if line.Words[0].BoundingBox != lines[i] {
+					t.Errorf("line %d word box = %+v, want %+v", i, line.Words[0].BoundingBox, lines[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRegionTilesMapToOriginalCoordinates(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 1000, 1000))
+	for y := 0; y < 1000; y++ {
+		for x := 0; x < 1000; x++ {
+			img.Set(x, y, color.White)
+		}
+	}
+	regions := []image.Rectangle{
+		image.Rect(100, 200, 500, 260),
+		image.Rect(300, 700, 900, 760),
+	}
+	lines := []types.BoundingBox{
+		{Left: 110, Top: 215, Width: 200, Height: 20},
+		{Left: 320, Top: 720, Width: 400, Height: 24},
+	}
+
+	tiles := RegionTiles(img, regions)
+	tileLines := make([][]types.OcrLine, len(tiles))
+	for i, tile := range tiles {
+		processed, err := NewImagePipeline(GrayscaleStage()).Apply(tile.Image)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tileLines[i] = MapTileLines(tile, processed.Bounds(), recognizeTileLines(tile, lines, 1))
+	}
+	stitched := StitchTileLines(tiles, tileLines)
+
+	if len(stitched) != len(lines) {
+		t.Fatalf("got %d lines, want %d: %+v", len(stitched), len(lines), stitched)
+	}
+	for i, line := range stitched {
+		if line.BoundingBox != lines[i] {
+			t.Errorf("line %d box = %+v, want %+v", i, line.BoundingBox, lines[i])
+		}
+	}
+}